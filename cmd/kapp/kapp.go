@@ -7,6 +7,7 @@ import (
 
 	"github.com/cppforlife/go-cli-ui/ui"
 	"github.com/k14s/kapp/pkg/kapp/cmd"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
 
 	// Import to initialize client auth plugins.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
@@ -25,6 +26,11 @@ func main() {
 
 	err := command.Execute()
 	if err != nil {
+		// Errors that carry exit status are not failures
+		// (e.g. presence of changes with --diff-exit-status)
+		if typedErr, ok := err.(cmdcore.ExitStatus); ok {
+			os.Exit(typedErr.ExitStatus())
+		}
 		confUI.ErrorLinef("Error: %v", err)
 		os.Exit(1)
 	}
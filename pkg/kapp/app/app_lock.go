@@ -0,0 +1,55 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	appLockAnnKey = "kapp.k14s.io/lock"
+)
+
+// AppLock is a lease-style lock that prevents concurrent deploys of the same app.
+// It is stored as an annotation on app's state object (e.g. ConfigMap).
+type AppLock struct {
+	Holder    string    `json:"holder"`
+	StartedAt time.Time `json:"startedAt"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func NewAppLock(holder string, ttl time.Duration) AppLock {
+	now := time.Now().UTC()
+	return AppLock{Holder: holder, StartedAt: now, ExpiresAt: now.Add(ttl)}
+}
+
+func NewAppLockFromAnns(anns map[string]string) (*AppLock, error) {
+	val, found := anns[appLockAnnKey]
+	if !found {
+		return nil, nil
+	}
+
+	var lock AppLock
+
+	err := json.Unmarshal([]byte(val), &lock)
+	if err != nil {
+		return nil, fmt.Errorf("Parsing app lock: %s", err)
+	}
+
+	return &lock, nil
+}
+
+func (l AppLock) Expired() bool { return time.Now().UTC().After(l.ExpiresAt) }
+
+func (l AppLock) Equal(other AppLock) bool {
+	return l.Holder == other.Holder && l.StartedAt.Equal(other.StartedAt) && l.ExpiresAt.Equal(other.ExpiresAt)
+}
+
+func (l AppLock) AsString() string {
+	bytes, err := json.Marshal(l)
+	if err != nil {
+		panic(fmt.Sprintf("Encoding app lock: %s", err))
+	}
+
+	return string(bytes)
+}
@@ -13,10 +13,10 @@ type AppMeta struct {
 	LastChange     ChangeMeta `json:"lastChange,omitempty"`
 }
 
-func NewAppMetaFromData(data map[string]string) (AppMeta, error) {
+func NewAppMetaFromData(data map[string][]byte) (AppMeta, error) {
 	var meta AppMeta
 
-	err := json.Unmarshal([]byte(data["spec"]), &meta)
+	err := json.Unmarshal(data["spec"], &meta)
 	if err != nil {
 		return AppMeta{}, fmt.Errorf("Parsing app metadata: %s", err)
 	}
@@ -33,8 +33,8 @@ func (m AppMeta) AsString() string {
 	return string(bytes)
 }
 
-func (m AppMeta) AsData() map[string]string {
-	return map[string]string{"spec": m.AsString()}
+func (m AppMeta) AsData() map[string][]byte {
+	return map[string][]byte{"spec": []byte(m.AsString())}
 }
 
 func (m AppMeta) Labels() map[string]string {
@@ -6,9 +6,7 @@ import (
 
 	"github.com/k14s/kapp/pkg/kapp/logger"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -18,15 +16,15 @@ const (
 
 type Apps struct {
 	nsName              string
-	coreClient          kubernetes.Interface
+	storage             StateStorage
 	identifiedResources ctlres.IdentifiedResources
 	logger              logger.Logger
 }
 
-func NewApps(nsName string, coreClient kubernetes.Interface,
+func NewApps(nsName string, storage StateStorage,
 	identifiedResources ctlres.IdentifiedResources, logger logger.Logger) Apps {
 
-	return Apps{nsName, coreClient, identifiedResources, logger}
+	return Apps{nsName, storage, identifiedResources, logger}
 }
 
 func (a Apps) Find(name string) (App, error) {
@@ -48,8 +46,8 @@ func (a Apps) Find(name string) (App, error) {
 		return nil, fmt.Errorf("Expected non-empty namespace")
 	}
 
-	return &RecordedApp{name, a.nsName, a.coreClient,
-		a.identifiedResources, nil, a.logger.NewPrefixed("RecordedApp")}, nil
+	return &RecordedApp{name, a.nsName, a.storage,
+		a.identifiedResources, nil, nil, a.logger.NewPrefixed("RecordedApp")}, nil
 }
 
 func (a Apps) List(additionalLabels map[string]string) ([]App, error) {
@@ -63,18 +61,14 @@ func (a Apps) List(additionalLabels map[string]string) ([]App, error) {
 		filterLabels[k] = v
 	}
 
-	listOpts := metav1.ListOptions{
-		LabelSelector: labels.Set(filterLabels).String(),
-	}
-
-	apps, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).List(listOpts)
+	apps, err := a.storage.List(a.nsName, filterLabels)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, app := range apps.Items {
-		recordedApp := &RecordedApp{app.Name, app.Namespace, a.coreClient,
-			a.identifiedResources, nil, a.logger.NewPrefixed("RecordedApp")}
+	for _, app := range apps {
+		recordedApp := &RecordedApp{app.Name, app.Namespace, a.storage,
+			a.identifiedResources, nil, nil, a.logger.NewPrefixed("RecordedApp")}
 
 		recordedApp.setMeta(app)
 
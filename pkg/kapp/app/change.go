@@ -4,16 +4,16 @@ import (
 	"fmt"
 	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 )
 
 type ChangeImpl struct {
-	name   string
-	nsName string
+	name    string
+	nsName  string
+	appName string
 
-	coreClient kubernetes.Interface
-	meta       ChangeMeta
+	storage StateStorage
+	meta    ChangeMeta
 
 	createdAt time.Time
 }
@@ -41,8 +41,21 @@ func (c *ChangeImpl) Succeed() error {
 	})
 }
 
+func (c *ChangeImpl) RecordResources(resources []ctlres.Resource) error {
+	return c.resources().Save(resources)
+}
+
+func (c *ChangeImpl) Resources() ([]ctlres.Resource, error) {
+	return c.resources().List()
+}
+
 func (c *ChangeImpl) Delete() error {
-	err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Delete(c.name, &metav1.DeleteOptions{})
+	err := c.resources().Delete()
+	if err != nil {
+		return err
+	}
+
+	err = c.storage.Delete(c.nsName, c.name)
 	if err != nil {
 		return fmt.Errorf("Deleting app change: %s", err)
 	}
@@ -50,8 +63,12 @@ func (c *ChangeImpl) Delete() error {
 	return nil
 }
 
+func (c *ChangeImpl) resources() ChangeResources {
+	return NewChangeResources(c.nsName, c.appName, c.name, c.storage)
+}
+
 func (c *ChangeImpl) update(doFunc func(*ChangeMeta)) error {
-	change, err := c.coreClient.CoreV1().ConfigMaps(c.nsName).Get(c.name, metav1.GetOptions{})
+	change, err := c.storage.Get(c.nsName, c.name)
 	if err != nil {
 		return fmt.Errorf("Getting app change: %s", err)
 	}
@@ -62,7 +79,7 @@ func (c *ChangeImpl) update(doFunc func(*ChangeMeta)) error {
 	c.meta = meta
 	change.Data = meta.AsData()
 
-	_, err = c.coreClient.CoreV1().ConfigMaps(c.nsName).Update(change)
+	_, err = c.storage.Update(c.nsName, change)
 	if err != nil {
 		return fmt.Errorf("Updating app change: %s", err)
 	}
@@ -79,3 +96,9 @@ func (NoopChange) Meta() ChangeMeta { return ChangeMeta{} }
 func (NoopChange) Fail() error      { return nil }
 func (NoopChange) Succeed() error   { return nil }
 func (NoopChange) Delete() error    { return nil }
+
+func (NoopChange) RecordResources([]ctlres.Resource) error { return nil }
+
+func (NoopChange) Resources() ([]ctlres.Resource, error) {
+	return nil, fmt.Errorf("Resources are not recorded for non-recorded apps")
+}
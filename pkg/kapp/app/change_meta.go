@@ -14,6 +14,18 @@ type ChangeMeta struct {
 	Description string `json:"description,omitempty"`
 
 	Namespaces []string `json:"namespaces,omitempty"`
+
+	KappVersion string `json:"kappVersion,omitempty"`
+	User        string `json:"user,omitempty"`
+	Hostname    string `json:"hostname,omitempty"`
+
+	// Custom holds user provided metadata (e.g. commit SHA)
+	Custom map[string]string `json:"custom,omitempty"`
+
+	// Partial indicates that recorded resources do not represent entire
+	// app (e.g. only a subset of resources was deployed due to filters,
+	// or sensitive values were masked), hence cannot be deployed again
+	Partial bool `json:"partial,omitempty"`
 }
 
 func NewChangeMetaFromString(data string) ChangeMeta {
@@ -27,8 +39,8 @@ func NewChangeMetaFromString(data string) ChangeMeta {
 	return meta
 }
 
-func NewChangeMetaFromData(data map[string]string) ChangeMeta {
-	return NewChangeMetaFromString(data["spec"])
+func NewChangeMetaFromData(data map[string][]byte) ChangeMeta {
+	return NewChangeMetaFromString(string(data["spec"]))
 }
 
 func (m ChangeMeta) AsString() string {
@@ -40,6 +52,6 @@ func (m ChangeMeta) AsString() string {
 	return string(bytes)
 }
 
-func (m ChangeMeta) AsData() map[string]string {
-	return map[string]string{"spec": m.AsString()}
+func (m ChangeMeta) AsData() map[string][]byte {
+	return map[string][]byte{"spec": []byte(m.AsString())}
 }
@@ -0,0 +1,247 @@
+package app
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	isChangeResourcesLabelKey   = "kapp.k14s.io/is-app-change-resources"
+	isChangeResourcesLabelValue = ""
+	changeResourcesLabelKey     = "kapp.k14s.io/app-change-resources-change" // holds change name
+
+	changeResourcesChunkIdxAnnKey  = "kapp.k14s.io/app-change-resources-chunk-index"
+	changeResourcesNumChunksAnnKey = "kapp.k14s.io/app-change-resources-num-chunks"
+
+	changeResourcesDataKey = "resources.yml.gz"
+
+	// ConfigMap (and Secret) total size is limited to 1MB; leave
+	// plenty of room for metadata and other overhead
+	changeResourcesChunkSize = 512 * 1024
+)
+
+// ChangeResources stores gzipped set of resources associated
+// with an app change. Content is split across multiple state objects
+// (e.g. ConfigMaps) if it does not fit into a single one.
+type ChangeResources struct {
+	nsName     string
+	appName    string
+	changeName string
+
+	storage StateStorage
+}
+
+func NewChangeResources(nsName, appName, changeName string, storage StateStorage) ChangeResources {
+	return ChangeResources{nsName, appName, changeName, storage}
+}
+
+func (r ChangeResources) Save(resources []ctlres.Resource) error {
+	data, err := r.encode(resources)
+	if err != nil {
+		return fmt.Errorf("Encoding app change resources: %s", err)
+	}
+
+	chunks := r.split(data)
+
+	for i, chunk := range chunks {
+		err := r.saveChunk(i, len(chunks), chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CopyTo copies recorded resources (if any) to a different app change
+func (r ChangeResources) CopyTo(dst ChangeResources) error {
+	chunks, err := r.chunks()
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		idx, numChunks, err := r.chunkPosition(chunk)
+		if err != nil {
+			return err
+		}
+
+		err = dst.saveChunk(idx, numChunks, chunk.Data[changeResourcesDataKey])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r ChangeResources) List() ([]ctlres.Resource, error) {
+	chunks, err := r.chunks()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("App change '%s' (namespace: %s) does not have recorded resources", r.changeName, r.nsName)
+	}
+
+	var data []byte
+
+	for i, chunk := range chunks {
+		idx, numChunks, err := r.chunkPosition(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if idx != i || numChunks != len(chunks) {
+			return nil, fmt.Errorf("Expected app change '%s' to have %d resources chunks, but found %d",
+				r.changeName, numChunks, len(chunks))
+		}
+
+		data = append(data, chunk.Data[changeResourcesDataKey]...)
+	}
+
+	resources, err := r.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("Decoding app change resources: %s", err)
+	}
+
+	return resources, nil
+}
+
+func (r ChangeResources) Delete() error {
+	chunks, err := r.chunks()
+	if err != nil {
+		return err
+	}
+
+	for _, chunk := range chunks {
+		err := r.storage.Delete(r.nsName, chunk.Name)
+		if err != nil {
+			return fmt.Errorf("Deleting app change resources: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func (r ChangeResources) saveChunk(idx, numChunks int, data []byte) error {
+	chunk := StateObject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-resources-%d", r.changeName, idx),
+			Namespace: r.nsName,
+			Labels: map[string]string{
+				isChangeResourcesLabelKey: isChangeResourcesLabelValue,
+				changeResourcesLabelKey:   r.changeName,
+				changeLabelKey:            r.appName,
+			},
+			Annotations: map[string]string{
+				changeResourcesChunkIdxAnnKey:  strconv.Itoa(idx),
+				changeResourcesNumChunksAnnKey: strconv.Itoa(numChunks),
+			},
+		},
+		Data: map[string][]byte{changeResourcesDataKey: data},
+	}
+
+	_, err := r.storage.Create(r.nsName, chunk)
+	if err != nil {
+		return fmt.Errorf("Creating app change resources: %s", err)
+	}
+
+	return nil
+}
+
+func (r ChangeResources) chunks() ([]StateObject, error) {
+	chunks, err := r.storage.List(r.nsName, map[string]string{
+		isChangeResourcesLabelKey: isChangeResourcesLabelValue,
+		changeResourcesLabelKey:   r.changeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Listing app change resources: %s", err)
+	}
+
+	// Positions are validated when chunks are joined
+	sort.Slice(chunks, func(i, j int) bool {
+		iIdx, _, _ := r.chunkPosition(chunks[i])
+		jIdx, _, _ := r.chunkPosition(chunks[j])
+		return iIdx < jIdx
+	})
+
+	return chunks, nil
+}
+
+func (r ChangeResources) chunkPosition(chunk StateObject) (int, int, error) {
+	idx, err := strconv.Atoi(chunk.Annotations[changeResourcesChunkIdxAnnKey])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Expected annotation '%s' on %s '%s' to be an integer",
+			changeResourcesChunkIdxAnnKey, r.storage.Kind(), chunk.Name)
+	}
+
+	numChunks, err := strconv.Atoi(chunk.Annotations[changeResourcesNumChunksAnnKey])
+	if err != nil {
+		return 0, 0, fmt.Errorf("Expected annotation '%s' on %s '%s' to be an integer",
+			changeResourcesNumChunksAnnKey, r.storage.Kind(), chunk.Name)
+	}
+
+	return idx, numChunks, nil
+}
+
+func (ChangeResources) encode(resources []ctlres.Resource) ([]byte, error) {
+	var buf bytes.Buffer
+
+	gzipWriter := gzip.NewWriter(&buf)
+
+	for _, res := range resources {
+		resBs, err := res.AsYAMLBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = gzipWriter.Write(append([]byte("---\n"), resBs...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := gzipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (ChangeResources) decode(data []byte) ([]ctlres.Resource, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	defer gzipReader.Close()
+
+	docsBs, err := ioutil.ReadAll(gzipReader)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctlres.NewFileResource(ctlres.NewBytesSource(docsBs)).Resources()
+}
+
+func (ChangeResources) split(data []byte) [][]byte {
+	// Always produce at least one chunk so that
+	// empty set of resources is recorded as well
+	chunks := [][]byte{}
+
+	for len(data) > changeResourcesChunkSize {
+		chunks = append(chunks, data[:changeResourcesChunkSize])
+		data = data[changeResourcesChunkSize:]
+	}
+
+	return append(chunks, data)
+}
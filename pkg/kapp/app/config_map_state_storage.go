@@ -0,0 +1,100 @@
+package app
+
+import (
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapStateStorage stores app state in ConfigMaps
+type ConfigMapStateStorage struct {
+	coreClient kubernetes.Interface
+}
+
+var _ StateStorage = ConfigMapStateStorage{}
+
+func NewConfigMapStateStorage(coreClient kubernetes.Interface) ConfigMapStateStorage {
+	return ConfigMapStateStorage{coreClient}
+}
+
+func (s ConfigMapStateStorage) Kind() string { return StateKindConfigMap }
+
+func (s ConfigMapStateStorage) Create(nsName string, obj StateObject) (StateObject, error) {
+	cm, err := s.coreClient.CoreV1().ConfigMaps(nsName).Create(s.toConfigMap(obj))
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromConfigMap(*cm), nil
+}
+
+func (s ConfigMapStateStorage) Get(nsName, name string) (StateObject, error) {
+	cm, err := s.coreClient.CoreV1().ConfigMaps(nsName).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromConfigMap(*cm), nil
+}
+
+func (s ConfigMapStateStorage) Update(nsName string, obj StateObject) (StateObject, error) {
+	cm, err := s.coreClient.CoreV1().ConfigMaps(nsName).Update(s.toConfigMap(obj))
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromConfigMap(*cm), nil
+}
+
+func (s ConfigMapStateStorage) Delete(nsName, name string) error {
+	return s.coreClient.CoreV1().ConfigMaps(nsName).Delete(name, &metav1.DeleteOptions{})
+}
+
+func (s ConfigMapStateStorage) List(nsName string, lbls map[string]string) ([]StateObject, error) {
+	listOpts := metav1.ListOptions{LabelSelector: labels.Set(lbls).String()}
+
+	cms, err := s.coreClient.CoreV1().ConfigMaps(nsName).List(listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []StateObject
+	for _, cm := range cms.Items {
+		result = append(result, s.fromConfigMap(cm))
+	}
+	return result, nil
+}
+
+func (ConfigMapStateStorage) toConfigMap(obj StateObject) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{ObjectMeta: obj.ObjectMeta}
+
+	// ConfigMap keeps text and binary content separately
+	for key, val := range obj.Data {
+		if utf8.Valid(val) {
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[key] = string(val)
+		} else {
+			if cm.BinaryData == nil {
+				cm.BinaryData = map[string][]byte{}
+			}
+			cm.BinaryData[key] = val
+		}
+	}
+
+	return cm
+}
+
+func (ConfigMapStateStorage) fromConfigMap(cm corev1.ConfigMap) StateObject {
+	obj := StateObject{ObjectMeta: cm.ObjectMeta, Data: map[string][]byte{}}
+
+	for key, val := range cm.Data {
+		obj.Data[key] = []byte(val)
+	}
+	for key, val := range cm.BinaryData {
+		obj.Data[key] = val
+	}
+
+	return obj
+}
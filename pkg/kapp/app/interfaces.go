@@ -1,6 +1,9 @@
 package app
 
 import (
+	"time"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -13,7 +16,15 @@ type App interface {
 	CreateOrUpdate(map[string]string) error
 	Exists() (bool, error)
 	Delete() error
-	Rename(string) error
+	// Release removes app record (and its changes) while keeping app resources
+	Release() error
+	Rename(newName string, newNsName string) error
+
+	// Lock is released either via Unlock or once app change is finished
+	Lock(holder string, ttl time.Duration) error
+	Unlock() error
+	CurrentLock() (*AppLock, error)
+	ForceUnlock() error
 
 	// Sorted as first is oldest
 	Changes() ([]Change, error)
@@ -29,5 +40,8 @@ type Change interface {
 	Fail() error
 	Succeed() error
 
+	RecordResources([]ctlres.Resource) error
+	Resources() ([]ctlres.Resource, error)
+
 	Delete() error
 }
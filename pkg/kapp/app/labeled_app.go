@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 	"k8s.io/apimachinery/pkg/labels"
@@ -54,7 +55,14 @@ func (a *LabeledApp) Delete() error {
 	return nil
 }
 
-func (a *LabeledApp) Rename(_ string) error { return fmt.Errorf("Not supported") }
+func (a *LabeledApp) Release() error { return fmt.Errorf("Not supported") }
+
+func (a *LabeledApp) Rename(_ string, _ string) error { return fmt.Errorf("Not supported") }
+
+func (a *LabeledApp) Lock(_ string, _ time.Duration) error { return nil }
+func (a *LabeledApp) Unlock() error                        { return nil }
+func (a *LabeledApp) CurrentLock() (*AppLock, error)       { return nil, nil }
+func (a *LabeledApp) ForceUnlock() error                   { return fmt.Errorf("Not supported") }
 
 func (a *LabeledApp) Meta() (AppMeta, error) { return AppMeta{}, nil }
 
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+)
+
+// Origin identifies kapp process that makes changes to an app
+type Origin struct {
+	User     string
+	Hostname string
+	PID      int
+}
+
+func CurrentOrigin() Origin {
+	origin := Origin{User: "unknown", Hostname: "unknown", PID: os.Getpid()}
+
+	if currUser, err := user.Current(); err == nil {
+		origin.User = currUser.Username
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		origin.Hostname = hostname
+	}
+
+	return origin
+}
+
+func (o Origin) String() string {
+	return fmt.Sprintf("%s@%s (pid %d)", o.User, o.Hostname, o.PID)
+}
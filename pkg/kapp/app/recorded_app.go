@@ -6,11 +6,9 @@ import (
 
 	"github.com/k14s/kapp/pkg/kapp/logger"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -21,10 +19,11 @@ type RecordedApp struct {
 	name   string
 	nsName string
 
-	coreClient          kubernetes.Interface
+	storage             StateStorage
 	identifiedResources ctlres.IdentifiedResources
 
 	memoizedMeta *AppMeta
+	heldLock     *AppLock
 	logger       logger.Logger
 }
 
@@ -45,7 +44,7 @@ func (a *RecordedApp) LabelSelector() (labels.Selector, error) {
 func (a *RecordedApp) CreateOrUpdate(labels map[string]string) error {
 	defer a.logger.DebugFunc("CreateOrUpdate").Finish()
 
-	configMap := &corev1.ConfigMap{
+	app := StateObject{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      a.name,
 			Namespace: a.nsName,
@@ -59,25 +58,25 @@ func (a *RecordedApp) CreateOrUpdate(labels map[string]string) error {
 		}.AsData(),
 	}
 
-	err := a.mergeAppUpdates(configMap, labels)
+	err := a.mergeAppUpdates(&app, labels)
 	if err != nil {
 		return err
 	}
 
-	_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Create(configMap)
+	_, err = a.storage.Create(a.nsName, app)
 	if err != nil {
 		if errors.IsAlreadyExists(err) {
-			existingConfigMap, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(a.name, metav1.GetOptions{})
+			existingApp, err := a.storage.Get(a.nsName, a.name)
 			if err != nil {
 				return fmt.Errorf("Getting app: %s", err)
 			}
 
-			err = a.mergeAppUpdates(existingConfigMap, labels)
+			err = a.mergeAppUpdates(&existingApp, labels)
 			if err != nil {
 				return err
 			}
 
-			_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Update(existingConfigMap)
+			_, err = a.storage.Update(a.nsName, existingApp)
 			if err != nil {
 				return fmt.Errorf("Updating app: %s", err)
 			}
@@ -91,21 +90,21 @@ func (a *RecordedApp) CreateOrUpdate(labels map[string]string) error {
 	return nil
 }
 
-func (a *RecordedApp) mergeAppUpdates(cm *corev1.ConfigMap, labels map[string]string) error {
+func (a *RecordedApp) mergeAppUpdates(app *StateObject, labels map[string]string) error {
 	for key, val := range labels {
-		if prevVal, found := cm.ObjectMeta.Labels[key]; found {
+		if prevVal, found := app.ObjectMeta.Labels[key]; found {
 			if prevVal != val {
 				return fmt.Errorf("Expected label '%s' value to remain same", key)
 			}
 		}
-		cm.ObjectMeta.Labels[key] = val
+		app.ObjectMeta.Labels[key] = val
 	}
 
 	return nil
 }
 
 func (a *RecordedApp) Exists() (bool, error) {
-	_, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(a.name, metav1.GetOptions{})
+	_, err := a.storage.Get(a.nsName, a.name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return false, nil
@@ -122,7 +121,7 @@ func (a *RecordedApp) Delete() error {
 		return err
 	}
 
-	err = NewRecordedAppChanges(a.nsName, a.name, a.coreClient).DeleteAll()
+	err = NewRecordedAppChanges(a.nsName, a.name, a.storage).DeleteAll()
 	if err != nil {
 		return fmt.Errorf("Deleting app changes: %s", err)
 	}
@@ -132,7 +131,7 @@ func (a *RecordedApp) Delete() error {
 		return err
 	}
 
-	err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(a.name, &metav1.DeleteOptions{})
+	err = a.storage.Delete(a.nsName, a.name)
 	if err != nil {
 		return fmt.Errorf("Deleting app: %s", err)
 	}
@@ -140,36 +139,17 @@ func (a *RecordedApp) Delete() error {
 	return nil
 }
 
-func (a *RecordedApp) Rename(newName string) error {
-	app, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(a.name, metav1.GetOptions{})
+func (a *RecordedApp) Release() error {
+	err := NewRecordedAppChanges(a.nsName, a.name, a.storage).DeleteAll()
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return fmt.Errorf("App '%s' (namespace: %s) does not exist: %s", a.name, a.nsName, err)
-		}
-		return fmt.Errorf("Getting app: %s", err)
-	}
-
-	// Clear out all existing meta fields
-	app.ObjectMeta = metav1.ObjectMeta{
-		Name:        newName,
-		Namespace:   a.nsName,
-		Labels:      app.ObjectMeta.Labels,
-		Annotations: app.ObjectMeta.Annotations,
-	}
-
-	_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Create(app)
-	if err != nil {
-		return fmt.Errorf("Creating app: %s", err)
+		return fmt.Errorf("Deleting app changes: %s", err)
 	}
 
-	err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(a.name, &metav1.DeleteOptions{})
+	err = a.storage.Delete(a.nsName, a.name)
 	if err != nil {
-		// TODO Do not clean up new config map as there is no gurantee it can be deleted either
 		return fmt.Errorf("Deleting app: %s", err)
 	}
 
-	// TODO deal with app history somehow?
-
 	return nil
 }
 
@@ -186,12 +166,12 @@ func (a *RecordedApp) labeledApp() (*LabeledApp, error) {
 
 func (a *RecordedApp) Meta() (AppMeta, error) { return a.meta() }
 
-func (a *RecordedApp) setMeta(app corev1.ConfigMap) (AppMeta, error) {
+func (a *RecordedApp) setMeta(app StateObject) (AppMeta, error) {
 	meta, err := NewAppMetaFromData(app.Data)
 	if err != nil {
-		errMsg := "App '%s' (namespace: %s) backed by ConfigMap '%s' did not contain parseable app metadata: %s"
-		hintText := " (hint: ConfigMap was overriden by another user?)"
-		return AppMeta{}, fmt.Errorf(errMsg+hintText, a.name, a.nsName, a.name, err)
+		errMsg := "App '%s' (namespace: %s) backed by %s '%s' did not contain parseable app metadata: %s"
+		hintText := " (hint: %s was overriden by another user?)"
+		return AppMeta{}, fmt.Errorf(errMsg+hintText, a.name, a.nsName, a.storage.Kind(), a.name, err, a.storage.Kind())
 	}
 
 	a.memoizedMeta = &meta
@@ -205,7 +185,7 @@ func (a *RecordedApp) meta() (AppMeta, error) {
 		return *a.memoizedMeta, nil
 	}
 
-	app, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(a.name, metav1.GetOptions{})
+	app, err := a.storage.Get(a.nsName, a.name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return AppMeta{}, fmt.Errorf("App '%s' (namespace: %s) does not exist: %s", a.name, a.nsName, err)
@@ -213,11 +193,11 @@ func (a *RecordedApp) meta() (AppMeta, error) {
 		return AppMeta{}, fmt.Errorf("Getting app: %s", err)
 	}
 
-	return a.setMeta(*app)
+	return a.setMeta(app)
 }
 
 func (a *RecordedApp) Changes() ([]Change, error) {
-	return NewRecordedAppChanges(a.nsName, a.name, a.coreClient).List()
+	return NewRecordedAppChanges(a.nsName, a.name, a.storage).List()
 }
 
 func (a *RecordedApp) LastChange() (Change, error) {
@@ -231,17 +211,18 @@ func (a *RecordedApp) LastChange() (Change, error) {
 	}
 
 	change := &ChangeImpl{
-		name:       meta.LastChangeName,
-		nsName:     a.nsName,
-		coreClient: a.coreClient,
-		meta:       meta.LastChange,
+		name:    meta.LastChangeName,
+		nsName:  a.nsName,
+		appName: a.name,
+		storage: a.storage,
+		meta:    meta.LastChange,
 	}
 
 	return change, nil
 }
 
 func (a *RecordedApp) BeginChange(meta ChangeMeta) (Change, error) {
-	change, err := NewRecordedAppChanges(a.nsName, a.name, a.coreClient).Begin(meta)
+	change, err := NewRecordedAppChanges(a.nsName, a.name, a.storage).Begin(meta)
 	if err != nil {
 		return nil, err
 	}
@@ -258,21 +239,21 @@ func (a *RecordedApp) BeginChange(meta ChangeMeta) (Change, error) {
 }
 
 func (a *RecordedApp) update(doFunc func(*AppMeta)) error {
-	change, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Get(a.name, metav1.GetOptions{})
+	app, err := a.storage.Get(a.nsName, a.name)
 	if err != nil {
 		return fmt.Errorf("Getting app: %s", err)
 	}
 
-	meta, err := NewAppMetaFromData(change.Data)
+	meta, err := NewAppMetaFromData(app.Data)
 	if err != nil {
 		return err
 	}
 
 	doFunc(&meta)
 
-	change.Data = meta.AsData()
+	app.Data = meta.AsData()
 
-	_, err = a.coreClient.CoreV1().ConfigMaps(a.nsName).Update(change)
+	_, err = a.storage.Update(a.nsName, app)
 	if err != nil {
 		return fmt.Errorf("Updating app: %s", err)
 	}
@@ -312,6 +293,14 @@ func (c appTrackingChange) Succeed() error {
 	return err
 }
 
+func (c appTrackingChange) RecordResources(resources []ctlres.Resource) error {
+	return c.change.RecordResources(resources)
+}
+
+func (c appTrackingChange) Resources() ([]ctlres.Resource, error) {
+	return c.change.Resources()
+}
+
 func (c appTrackingChange) Delete() error {
 	return c.change.Delete()
 }
@@ -5,10 +5,7 @@ import (
 	"sort"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/client-go/kubernetes"
 )
 
 const (
@@ -21,41 +18,34 @@ type RecordedAppChanges struct {
 	nsName  string
 	appName string
 
-	coreClient kubernetes.Interface
+	storage StateStorage
 }
 
-func NewRecordedAppChanges(nsName, appName string, coreClient kubernetes.Interface) RecordedAppChanges {
-	return RecordedAppChanges{nsName, appName, coreClient}
+func NewRecordedAppChanges(nsName, appName string, storage StateStorage) RecordedAppChanges {
+	return RecordedAppChanges{nsName, appName, storage}
 }
 
 func (a RecordedAppChanges) List() ([]Change, error) {
 	var result []Change
 
-	listOpts := metav1.ListOptions{
-		LabelSelector: labels.Set(map[string]string{
-			isChangeLabelKey: isChangeLabelValue,
-			changeLabelKey:   a.appName,
-		}).String(),
-	}
-
-	changes, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).List(listOpts)
+	changes, err := a.storage.List(a.nsName, map[string]string{
+		isChangeLabelKey: isChangeLabelValue,
+		changeLabelKey:   a.appName,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	sort.Slice(changes.Items, func(i, j int) bool {
-		iT := &changes.Items[i].CreationTimestamp
-		jT := &changes.Items[j].CreationTimestamp
-		return iT.Before(jT)
-	})
+	a.sort(changes)
 
-	for _, change := range changes.Items {
+	for _, change := range changes {
 		result = append(result, &ChangeImpl{
-			name:       change.Name,
-			nsName:     a.nsName,
-			coreClient: a.coreClient,
-			meta:       NewChangeMetaFromData(change.Data),
-			createdAt:  change.CreationTimestamp.Time,
+			name:      change.Name,
+			nsName:    a.nsName,
+			appName:   a.appName,
+			storage:   a.storage,
+			meta:      NewChangeMetaFromData(change.Data),
+			createdAt: change.CreationTimestamp.Time,
 		})
 	}
 
@@ -63,20 +53,33 @@ func (a RecordedAppChanges) List() ([]Change, error) {
 }
 
 func (a RecordedAppChanges) DeleteAll() error {
-	listOpts := metav1.ListOptions{
-		LabelSelector: labels.Set(map[string]string{
-			isChangeLabelKey: isChangeLabelValue,
-			changeLabelKey:   a.appName,
-		}).String(),
+	changes, err := a.storage.List(a.nsName, map[string]string{
+		isChangeLabelKey: isChangeLabelValue,
+		changeLabelKey:   a.appName,
+	})
+	if err != nil {
+		return err
 	}
 
-	changes, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).List(listOpts)
+	for _, change := range changes {
+		err := a.storage.Delete(a.nsName, change.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Delete recorded resources for all changes (even ones
+	// whose change may have been already deleted)
+	changeResources, err := a.storage.List(a.nsName, map[string]string{
+		isChangeResourcesLabelKey: isChangeResourcesLabelValue,
+		changeLabelKey:            a.appName,
+	})
 	if err != nil {
 		return err
 	}
 
-	for _, change := range changes.Items {
-		err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Delete(change.Name, &metav1.DeleteOptions{})
+	for _, changeRes := range changeResources {
+		err := a.storage.Delete(a.nsName, changeRes.Name)
 		if err != nil {
 			return err
 		}
@@ -85,14 +88,71 @@ func (a RecordedAppChanges) DeleteAll() error {
 	return nil
 }
 
+// CopyTo copies app changes (including their recorded resources)
+// to a different app. Returns mapping of previous to new change names.
+func (a RecordedAppChanges) CopyTo(dst RecordedAppChanges) (map[string]string, error) {
+	changes, err := a.storage.List(a.nsName, map[string]string{
+		isChangeLabelKey: isChangeLabelValue,
+		changeLabelKey:   a.appName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy in order so that new changes keep their relative order
+	a.sort(changes)
+
+	changeNames := map[string]string{}
+
+	for _, change := range changes {
+		labels := map[string]string{}
+		for k, v := range change.Labels {
+			labels[k] = v
+		}
+		labels[changeLabelKey] = dst.appName
+
+		newChange := StateObject{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: dst.appName + "-change-",
+				Namespace:    dst.nsName,
+				Labels:       labels,
+				Annotations:  change.Annotations,
+			},
+			Data: change.Data,
+		}
+
+		createdChange, err := dst.storage.Create(dst.nsName, newChange)
+		if err != nil {
+			return nil, fmt.Errorf("Creating app change: %s", err)
+		}
+
+		changeNames[change.Name] = createdChange.Name
+
+		srcResources := NewChangeResources(a.nsName, a.appName, change.Name, a.storage)
+		dstResources := NewChangeResources(dst.nsName, dst.appName, createdChange.Name, dst.storage)
+
+		err = srcResources.CopyTo(dstResources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return changeNames, nil
+}
+
 func (a RecordedAppChanges) Begin(meta ChangeMeta) (*ChangeImpl, error) {
 	newMeta := ChangeMeta{
 		StartedAt:   time.Now().UTC(),
 		Description: meta.Description,
 		Namespaces:  meta.Namespaces,
+		KappVersion: meta.KappVersion,
+		User:        meta.User,
+		Hostname:    meta.Hostname,
+		Custom:      meta.Custom,
+		Partial:     meta.Partial,
 	}
 
-	configMap := &corev1.ConfigMap{
+	change := StateObject{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: a.appName + "-change-",
 			Namespace:    a.nsName,
@@ -104,17 +164,30 @@ func (a RecordedAppChanges) Begin(meta ChangeMeta) (*ChangeImpl, error) {
 		Data: newMeta.AsData(),
 	}
 
-	createdChange, err := a.coreClient.CoreV1().ConfigMaps(a.nsName).Create(configMap)
+	createdChange, err := a.storage.Create(a.nsName, change)
 	if err != nil {
 		return nil, fmt.Errorf("Creating app change: %s", err)
 	}
 
-	change := &ChangeImpl{
-		name:       createdChange.Name,
-		nsName:     createdChange.Namespace,
-		coreClient: a.coreClient,
-		meta:       newMeta,
-	}
+	return &ChangeImpl{
+		name:    createdChange.Name,
+		nsName:  createdChange.Namespace,
+		appName: a.appName,
+		storage: a.storage,
+		meta:    newMeta,
+	}, nil
+}
 
-	return change, nil
+func (RecordedAppChanges) sort(changes []StateObject) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		iT := &changes[i].CreationTimestamp
+		jT := &changes[j].CreationTimestamp
+		if iT.Equal(jT) {
+			// Copied changes may share creation timestamp
+			iMeta := NewChangeMetaFromData(changes[i].Data)
+			jMeta := NewChangeMetaFromData(changes[j].Data)
+			return iMeta.StartedAt.Before(jMeta.StartedAt)
+		}
+		return iT.Before(jT)
+	})
 }
@@ -0,0 +1,93 @@
+package app_test
+
+import (
+	"fmt"
+	"testing"
+
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+)
+
+func TestRecordedAppChangesBeginRecordsPartial(t *testing.T) {
+	changes := ctlapp.NewRecordedAppChanges("ns", "app", newMemoryStateStorage())
+
+	_, err := changes.Begin(ctlapp.ChangeMeta{Description: "update", Partial: true})
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	recordedChanges, err := changes.List()
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	if len(recordedChanges) != 1 {
+		t.Fatalf("Expected one app change, but was %d", len(recordedChanges))
+	}
+
+	meta := recordedChanges[0].Meta()
+
+	if meta.Description != "update" {
+		t.Fatalf("Expected description to be recorded, but was '%s'", meta.Description)
+	}
+	if !meta.Partial {
+		t.Fatalf("Expected app change to be recorded as partial")
+	}
+}
+
+type memoryStateStorage struct {
+	objs map[string]ctlapp.StateObject
+}
+
+var _ ctlapp.StateStorage = &memoryStateStorage{}
+
+func newMemoryStateStorage() *memoryStateStorage {
+	return &memoryStateStorage{objs: map[string]ctlapp.StateObject{}}
+}
+
+func (s *memoryStateStorage) Kind() string { return "memory" }
+
+func (s *memoryStateStorage) Create(nsName string, obj ctlapp.StateObject) (ctlapp.StateObject, error) {
+	if len(obj.Name) == 0 {
+		obj.Name = fmt.Sprintf("%s%d", obj.GenerateName, len(s.objs))
+	}
+	obj.Namespace = nsName
+	s.objs[nsName+"/"+obj.Name] = obj
+	return obj, nil
+}
+
+func (s *memoryStateStorage) Get(nsName, name string) (ctlapp.StateObject, error) {
+	obj, found := s.objs[nsName+"/"+name]
+	if !found {
+		return ctlapp.StateObject{}, fmt.Errorf("Expected to find state object %s/%s", nsName, name)
+	}
+	return obj, nil
+}
+
+func (s *memoryStateStorage) Update(nsName string, obj ctlapp.StateObject) (ctlapp.StateObject, error) {
+	s.objs[nsName+"/"+obj.Name] = obj
+	return obj, nil
+}
+
+func (s *memoryStateStorage) Delete(nsName, name string) error {
+	delete(s.objs, nsName+"/"+name)
+	return nil
+}
+
+func (s *memoryStateStorage) List(nsName string, labels map[string]string) ([]ctlapp.StateObject, error) {
+	var result []ctlapp.StateObject
+	for _, obj := range s.objs {
+		if obj.Namespace != nsName {
+			continue
+		}
+		matches := true
+		for k, v := range labels {
+			if val, found := obj.Labels[k]; !found || val != v {
+				matches = false
+			}
+		}
+		if matches {
+			result = append(result, obj)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	appLockUpdateAttempts = 5
+)
+
+func (a *RecordedApp) Lock(holder string, ttl time.Duration) error {
+	defer a.logger.DebugFunc("Lock").Finish()
+
+	if a.heldLock != nil {
+		return fmt.Errorf("Expected app lock to not be already held")
+	}
+
+	lock := NewAppLock(holder, ttl)
+
+	err := a.updateLock(func(app *StateObject) (bool, error) {
+		existingLock, err := NewAppLockFromAnns(app.Annotations)
+		if err != nil {
+			return false, err
+		}
+
+		if existingLock != nil && !existingLock.Expired() {
+			return false, fmt.Errorf("App '%s' (namespace: %s) is being deployed by %s since %s (lock expires at %s)",
+				a.name, a.nsName, existingLock.Holder, existingLock.StartedAt, existingLock.ExpiresAt)
+		}
+
+		if app.Annotations == nil {
+			app.Annotations = map[string]string{}
+		}
+		app.Annotations[appLockAnnKey] = lock.AsString()
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.heldLock = &lock
+
+	return nil
+}
+
+// Unlock releases lock if it's held by this app instance;
+// lock that was broken and taken by someone else is left as is
+func (a *RecordedApp) Unlock() error {
+	defer a.logger.DebugFunc("Unlock").Finish()
+
+	if a.heldLock == nil {
+		return nil
+	}
+
+	heldLock := *a.heldLock
+
+	err := a.updateLock(func(app *StateObject) (bool, error) {
+		existingLock, err := NewAppLockFromAnns(app.Annotations)
+		if err != nil {
+			return false, err
+		}
+
+		if existingLock == nil || !existingLock.Equal(heldLock) {
+			return false, nil
+		}
+
+		delete(app.Annotations, appLockAnnKey)
+
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	a.heldLock = nil
+
+	return nil
+}
+
+func (a *RecordedApp) CurrentLock() (*AppLock, error) {
+	app, err := a.storage.Get(a.nsName, a.name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("App '%s' (namespace: %s) does not exist: %s", a.name, a.nsName, err)
+		}
+		return nil, fmt.Errorf("Getting app: %s", err)
+	}
+
+	return NewAppLockFromAnns(app.Annotations)
+}
+
+func (a *RecordedApp) ForceUnlock() error {
+	defer a.logger.DebugFunc("ForceUnlock").Finish()
+
+	return a.updateLock(func(app *StateObject) (bool, error) {
+		if _, found := app.Annotations[appLockAnnKey]; !found {
+			return false, nil
+		}
+
+		delete(app.Annotations, appLockAnnKey)
+
+		return true, nil
+	})
+}
+
+// updateLock relies on optimistic concurrency (resource version)
+// to guarantee that only a single holder is able to obtain the lock
+func (a *RecordedApp) updateLock(updateFunc func(*StateObject) (bool, error)) error {
+	for i := 0; ; i++ {
+		app, err := a.storage.Get(a.nsName, a.name)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// App is gone, hence there is nothing to lock or unlock
+				return nil
+			}
+			return fmt.Errorf("Getting app: %s", err)
+		}
+
+		changed, err := updateFunc(&app)
+		if err != nil || !changed {
+			return err
+		}
+
+		_, err = a.storage.Update(a.nsName, app)
+		if err != nil {
+			if errors.IsConflict(err) && i < appLockUpdateAttempts {
+				continue
+			}
+			return fmt.Errorf("Updating app lock: %s", err)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rename moves app and its app changes under a new name (and optionally
+// into a different state namespace). If any step fails, already created
+// copies are removed so that two apps never claim the same resources.
+func (a *RecordedApp) Rename(newName string, newNsName string) error {
+	defer a.logger.DebugFunc("Rename").Finish()
+
+	if len(newNsName) == 0 {
+		newNsName = a.nsName
+	}
+
+	if newName == a.name && newNsName == a.nsName {
+		return fmt.Errorf("Expected new app name or namespace to be different from current one")
+	}
+
+	app, err := a.storage.Get(a.nsName, a.name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("App '%s' (namespace: %s) does not exist: %s", a.name, a.nsName, err)
+		}
+		return fmt.Errorf("Getting app: %s", err)
+	}
+
+	lock, err := NewAppLockFromAnns(app.Annotations)
+	if err != nil {
+		return err
+	}
+
+	if lock != nil && !lock.Expired() {
+		return fmt.Errorf("App '%s' (namespace: %s) is being deployed by %s since %s",
+			a.name, a.nsName, lock.Holder, lock.StartedAt)
+	}
+
+	meta, err := NewAppMetaFromData(app.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.storage.Get(newNsName, newName)
+	if err == nil {
+		return fmt.Errorf("App '%s' (namespace: %s) already exists", newName, newNsName)
+	}
+	if !errors.IsNotFound(err) {
+		return fmt.Errorf("Getting app: %s", err)
+	}
+
+	prevChanges := NewRecordedAppChanges(a.nsName, a.name, a.storage)
+	newChanges := NewRecordedAppChanges(newNsName, newName, a.storage)
+
+	changeNames, err := prevChanges.CopyTo(newChanges)
+	if err != nil {
+		_ = newChanges.DeleteAll()
+		return fmt.Errorf("Copying app changes: %s", err)
+	}
+
+	if newChangeName, found := changeNames[meta.LastChangeName]; found {
+		meta.LastChangeName = newChangeName
+	}
+
+	delete(app.Annotations, appLockAnnKey)
+
+	// Clear out all existing meta fields
+	app.ObjectMeta = metav1.ObjectMeta{
+		Name:        newName,
+		Namespace:   newNsName,
+		Labels:      app.ObjectMeta.Labels,
+		Annotations: app.ObjectMeta.Annotations,
+	}
+	app.Data = meta.AsData()
+
+	_, err = a.storage.Create(newNsName, app)
+	if err != nil {
+		_ = newChanges.DeleteAll()
+		return fmt.Errorf("Creating app: %s", err)
+	}
+
+	err = a.storage.Delete(a.nsName, a.name)
+	if err != nil {
+		// Remove new app since it shares its label with previous app
+		_ = a.storage.Delete(newNsName, newName)
+		_ = newChanges.DeleteAll()
+		return fmt.Errorf("Deleting app: %s", err)
+	}
+
+	err = prevChanges.DeleteAll()
+	if err != nil {
+		return fmt.Errorf("Deleting app changes of previous app: %s", err)
+	}
+
+	a.name = newName
+	a.nsName = newNsName
+	a.memoizedMeta = nil
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretStateStorage stores app state in Secrets
+// (useful when access to ConfigMaps is not restricted enough)
+type SecretStateStorage struct {
+	coreClient kubernetes.Interface
+}
+
+var _ StateStorage = SecretStateStorage{}
+
+func NewSecretStateStorage(coreClient kubernetes.Interface) SecretStateStorage {
+	return SecretStateStorage{coreClient}
+}
+
+func (s SecretStateStorage) Kind() string { return StateKindSecret }
+
+func (s SecretStateStorage) Create(nsName string, obj StateObject) (StateObject, error) {
+	secret, err := s.coreClient.CoreV1().Secrets(nsName).Create(s.toSecret(obj))
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromSecret(*secret), nil
+}
+
+func (s SecretStateStorage) Get(nsName, name string) (StateObject, error) {
+	secret, err := s.coreClient.CoreV1().Secrets(nsName).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromSecret(*secret), nil
+}
+
+func (s SecretStateStorage) Update(nsName string, obj StateObject) (StateObject, error) {
+	secret, err := s.coreClient.CoreV1().Secrets(nsName).Update(s.toSecret(obj))
+	if err != nil {
+		return StateObject{}, err
+	}
+	return s.fromSecret(*secret), nil
+}
+
+func (s SecretStateStorage) Delete(nsName, name string) error {
+	return s.coreClient.CoreV1().Secrets(nsName).Delete(name, &metav1.DeleteOptions{})
+}
+
+func (s SecretStateStorage) List(nsName string, lbls map[string]string) ([]StateObject, error) {
+	listOpts := metav1.ListOptions{LabelSelector: labels.Set(lbls).String()}
+
+	secrets, err := s.coreClient.CoreV1().Secrets(nsName).List(listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []StateObject
+	for _, secret := range secrets.Items {
+		result = append(result, s.fromSecret(secret))
+	}
+	return result, nil
+}
+
+func (SecretStateStorage) toSecret(obj StateObject) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: obj.ObjectMeta,
+		Type:       corev1.SecretTypeOpaque,
+		Data:       obj.Data,
+	}
+}
+
+func (SecretStateStorage) fromSecret(secret corev1.Secret) StateObject {
+	obj := StateObject{ObjectMeta: secret.ObjectMeta, Data: secret.Data}
+	if obj.Data == nil {
+		obj.Data = map[string][]byte{}
+	}
+	return obj
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	StateKindConfigMap = "configmap"
+	StateKindSecret    = "secret"
+)
+
+// StateObject is a storage agnostic representation of
+// a single piece of app state (app, app change, etc.)
+type StateObject struct {
+	metav1.ObjectMeta
+	Data map[string][]byte
+}
+
+// StateStorage stores app state in a cluster. Returned errors
+// are API errors so they could be checked via k8s.io/apimachinery/pkg/api/errors.
+type StateStorage interface {
+	Kind() string
+
+	Create(nsName string, obj StateObject) (StateObject, error)
+	Get(nsName, name string) (StateObject, error)
+	Update(nsName string, obj StateObject) (StateObject, error)
+	Delete(nsName, name string) error
+	List(nsName string, labels map[string]string) ([]StateObject, error)
+}
+
+func NewStateStorage(kind string, coreClient kubernetes.Interface) (StateStorage, error) {
+	switch kind {
+	case StateKindConfigMap:
+		return NewConfigMapStateStorage(coreClient), nil
+	case StateKindSecret:
+		return NewSecretStateStorage(coreClient), nil
+	default:
+		return nil, fmt.Errorf("Unknown app state kind '%s' (supported: %s, %s)",
+			kind, StateKindConfigMap, StateKindSecret)
+	}
+}
@@ -1,16 +1,57 @@
 package app
 
+import (
+	"fmt"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"github.com/k14s/kapp/pkg/kapp/version"
+)
+
 type Touch struct {
 	App              App
 	Description      string
 	Namespaces       []string
 	IgnoreSuccessErr bool
+
+	// CustomMeta is recorded as part of app change metadata
+	CustomMeta map[string]string
+
+	// Resources (if non-nil) are recorded as part of app change
+	Resources []ctlres.Resource
+	// Partial indicates that Resources do not represent entire app
+	Partial bool
+
+	// KeepLockOnFailure indicates that app lock should stay held
+	// if app change fails (e.g. so that it could be rolled back)
+	KeepLockOnFailure bool
 }
 
 func (t Touch) Do(doFunc func() error) error {
+	err := t.do(doFunc)
+	if err != nil && t.KeepLockOnFailure {
+		return err
+	}
+
+	// Release app lock (if it's held) after app change is finished
+	unlockErr := t.App.Unlock()
+	if unlockErr != nil && err == nil {
+		return fmt.Errorf("Releasing app lock: %s", unlockErr)
+	}
+
+	return err
+}
+
+func (t Touch) do(doFunc func() error) error {
+	origin := CurrentOrigin()
+
 	meta := ChangeMeta{
 		Description: t.Description,
 		Namespaces:  t.Namespaces,
+		KappVersion: version.Version,
+		User:        origin.User,
+		Hostname:    origin.Hostname,
+		Custom:      t.CustomMeta,
+		Partial:     t.Partial,
 	}
 
 	change, err := t.App.BeginChange(meta)
@@ -18,6 +59,14 @@ func (t Touch) Do(doFunc func() error) error {
 		return err
 	}
 
+	if t.Resources != nil {
+		err := change.RecordResources(t.Resources)
+		if err != nil {
+			_ = change.Fail()
+			return err
+		}
+	}
+
 	workErr := doFunc()
 	if workErr != nil {
 		_ = change.Fail()
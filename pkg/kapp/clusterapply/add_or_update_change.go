@@ -2,6 +2,7 @@ package clusterapply
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
@@ -10,6 +11,8 @@ import (
 	ctlresm "github.com/k14s/kapp/pkg/kapp/resourcesmisc"
 	"github.com/k14s/kapp/pkg/kapp/util"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
@@ -17,10 +20,33 @@ const (
 	updateStrategyUpdateAnnValue            = ""
 	updateStrategyFallbackOnReplaceAnnValue = "fallback-on-replace"
 	updateStrategyAlwaysReplaceAnnValue     = "always-replace"
+	updateStrategyServerSideApplyAnnValue   = "server-side-apply"
+	updateStrategyMergePatchAnnValue        = "merge-patch"
+
+	serverSideApplyFieldManager = "kapp"
+
+	createStrategyAnnKey                   = "kapp.k14s.io/create-strategy"
+	createStrategyCreateAnnValue           = ""
+	createStrategyFallbackOnUpdateAnnValue = "fallback-on-update"
+	createStrategySkipIfExistsAnnValue     = "skip-if-exists"
 )
 
 type AddOrUpdateChangeOpts struct {
 	DefaultUpdateStrategy string
+	DefaultCreateStrategy string
+
+	// Used to check that resource that already exists is not
+	// associated with a different app before taking it over
+	AppLabelSelector           labels.Selector
+	SkipResourceOwnershipCheck bool
+}
+
+func (o AddOrUpdateChangeOpts) createStrategy(res ctlres.Resource) string {
+	strategy, found := res.Annotations()[createStrategyAnnKey]
+	if !found {
+		strategy = o.DefaultCreateStrategy
+	}
+	return strategy
 }
 
 type AddOrUpdateChange struct {
@@ -29,6 +55,7 @@ type AddOrUpdateChange struct {
 	changeFactory       ctldiff.ChangeFactory
 	changeSetFactory    ctldiff.ChangeSetFactory
 	opts                AddOrUpdateChangeOpts
+	retry               *ApplyRetry
 }
 
 func (c AddOrUpdateChange) Apply() error {
@@ -36,8 +63,28 @@ func (c AddOrUpdateChange) Apply() error {
 
 	switch op {
 	case ctldiff.ChangeOpAdd:
-		createdRes, err := c.identifiedResources.Create(c.change.NewResource())
+		strategy := c.opts.createStrategy(c.change.NewResource())
+
+		switch strategy {
+		case createStrategyCreateAnnValue, createStrategyFallbackOnUpdateAnnValue, createStrategySkipIfExistsAnnValue:
+		default:
+			return fmt.Errorf("Unknown create strategy: %s", strategy)
+		}
+
+		createdRes, retried, err := c.create(c.change.NewResource())
 		if err != nil {
+			if errors.IsAlreadyExists(err) {
+				if retried {
+					// Previous attempt may have created resource
+					return c.takeOver()
+				}
+				switch strategy {
+				case createStrategyFallbackOnUpdateAnnValue:
+					return c.takeOver()
+				case createStrategySkipIfExistsAnnValue:
+					return nil
+				}
+			}
 			return err
 		}
 
@@ -55,7 +102,7 @@ func (c AddOrUpdateChange) Apply() error {
 
 		switch strategy {
 		case updateStrategyUpdateAnnValue:
-			updatedRes, err := c.identifiedResources.Update(newRes)
+			updatedRes, err := c.update(newRes)
 			if err != nil {
 				if errors.IsConflict(err) {
 					return c.tryToResolveConflict(err)
@@ -69,7 +116,7 @@ func (c AddOrUpdateChange) Apply() error {
 			}
 
 		case updateStrategyFallbackOnReplaceAnnValue:
-			updatedRes, err := c.identifiedResources.Update(newRes)
+			updatedRes, err := c.update(newRes)
 			if err != nil {
 				if errors.IsInvalid(err) {
 					return c.replace()
@@ -85,6 +132,105 @@ func (c AddOrUpdateChange) Apply() error {
 		case updateStrategyAlwaysReplaceAnnValue:
 			return c.replace()
 
+		case updateStrategyServerSideApplyAnnValue:
+			// Applied resource is used (instead of the rebased one)
+			// so that kapp does not take ownership of copied fields
+			var updatedRes ctlres.Resource
+
+			_, err := c.retry.Do(func() error {
+				var err error
+				updatedRes, err = c.identifiedResources.ServerSideApply(
+					c.change.AppliedResource(), serverSideApplyFieldManager)
+				return err
+			})
+			if err != nil {
+				if errors.IsConflict(err) {
+					return c.serverSideApplyConflictErr(err)
+				}
+				return err
+			}
+
+			err = c.recordAppliedResource(updatedRes)
+			if err != nil {
+				return err
+			}
+
+		case updateStrategyMergePatchAnnValue:
+			patch, err := c.mergePatch(newRes)
+			if err != nil {
+				return err
+			}
+
+			var updatedRes ctlres.Resource
+
+			_, err = c.retry.Do(func() error {
+				var err error
+				updatedRes, err = c.identifiedResources.Patch(newRes, types.MergePatchType, patch)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			err = c.recordAppliedResource(updatedRes)
+			if err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("Unknown update strategy: %s", strategy)
+		}
+	}
+
+	return nil
+}
+
+// DryRun checks with the server whether change would be accepted without applying it
+func (c AddOrUpdateChange) DryRun() error {
+	switch c.change.Op() {
+	case ctldiff.ChangeOpAdd:
+		err := c.identifiedResources.DryRunCreate(c.change.NewResource())
+		if err != nil && errors.IsAlreadyExists(err) {
+			switch c.opts.createStrategy(c.change.NewResource()) {
+			case createStrategyFallbackOnUpdateAnnValue, createStrategySkipIfExistsAnnValue:
+				return nil // resource would be updated or left as is
+			}
+		}
+		return err
+
+	case ctldiff.ChangeOpUpdate:
+		newRes := c.change.NewResource()
+		strategy, found := newRes.Annotations()[updateStrategyAnnKey]
+		if !found {
+			strategy = c.opts.DefaultUpdateStrategy
+		}
+
+		switch strategy {
+		case updateStrategyUpdateAnnValue:
+			return c.identifiedResources.DryRunUpdate(newRes)
+
+		case updateStrategyFallbackOnReplaceAnnValue:
+			err := c.identifiedResources.DryRunUpdate(newRes)
+			if err != nil && errors.IsInvalid(err) {
+				return nil // resource would be replaced
+			}
+			return err
+
+		case updateStrategyAlwaysReplaceAnnValue:
+			// Resource cannot be checked since it has to be deleted first
+			return nil
+
+		case updateStrategyServerSideApplyAnnValue:
+			return c.identifiedResources.DryRunServerSideApply(
+				c.change.AppliedResource(), serverSideApplyFieldManager)
+
+		case updateStrategyMergePatchAnnValue:
+			patch, err := c.mergePatch(newRes)
+			if err != nil {
+				return err
+			}
+			return c.identifiedResources.DryRunPatch(newRes, types.MergePatchType, patch)
+
 		default:
 			return fmt.Errorf("Unknown update strategy: %s", strategy)
 		}
@@ -95,7 +241,9 @@ func (c AddOrUpdateChange) Apply() error {
 
 func (c AddOrUpdateChange) replace() error {
 	// TODO do we have to wait for delete to finish?
-	err := c.identifiedResources.Delete(c.change.ExistingResource())
+	_, err := c.retry.Do(func() error {
+		return c.identifiedResources.Delete(c.change.ExistingResource(), ctlres.DeleteOpts{})
+	})
 	if err != nil {
 		return err
 	}
@@ -112,14 +260,80 @@ func (c AddOrUpdateChange) replace() error {
 		time.Sleep(1 * time.Second)
 	}
 
-	updatedRes, err := c.identifiedResources.Create(c.change.AppliedResource())
+	updatedRes, retried, err := c.create(c.change.AppliedResource())
 	if err != nil {
+		if retried && errors.IsAlreadyExists(err) {
+			// Previous attempt may have created resource
+			return c.takeOver()
+		}
 		return err
 	}
 
 	return c.recordAppliedResource(updatedRes)
 }
 
+// create creates resource retrying transient errors. Since create request
+// may have been persisted before failing (e.g. timed out), returned bool
+// indicates whether AlreadyExists error could be caused by a retry.
+func (c AddOrUpdateChange) create(res ctlres.Resource) (ctlres.Resource, bool, error) {
+	var createdRes ctlres.Resource
+
+	retried, err := c.retry.Do(func() error {
+		var err error
+		createdRes, err = c.identifiedResources.Create(res)
+		return err
+	})
+
+	return createdRes, retried, err
+}
+
+// update updates resource retrying transient errors. Update that was
+// persisted before failing results in a conflict when retried
+// (resource version changed), which is resolved the same way as other conflicts.
+func (c AddOrUpdateChange) update(res ctlres.Resource) (ctlres.Resource, error) {
+	var updatedRes ctlres.Resource
+
+	_, err := c.retry.Do(func() error {
+		var err error
+		updatedRes, err = c.identifiedResources.Update(res)
+		return err
+	})
+
+	return updatedRes, err
+}
+
+// takeOver updates resource that was not found when changes were
+// calculated, but was created (e.g. by someone else) in the meantime
+func (c AddOrUpdateChange) takeOver() error {
+	existingRes, err := c.identifiedResources.Get(c.change.NewResource())
+	if err != nil {
+		return err
+	}
+
+	if !c.opts.SkipResourceOwnershipCheck {
+		if c.opts.AppLabelSelector == nil {
+			return fmt.Errorf("Expected app label selector to check ownership of resource %s",
+				existingRes.Description())
+		}
+
+		labeledResources := ctlres.NewLabeledResources(
+			c.opts.AppLabelSelector, c.identifiedResources, logger.NewTODOLogger())
+
+		err = labeledResources.CheckResourceOwnership([]ctlres.Resource{existingRes})
+		if err != nil {
+			return err
+		}
+	}
+
+	updateChange, err := c.changeFactory.NewChangeAgainstLastApplied(existingRes, c.change.AppliedResource())
+	if err != nil {
+		return err
+	}
+
+	return AddOrUpdateChange{updateChange, c.identifiedResources,
+		c.changeFactory, c.changeSetFactory, c.opts, c.retry}.Apply()
+}
+
 func (a AddOrUpdateChange) tryToResolveConflict(origErr error) error {
 	errMsgPrefix := "Failed to update due to resource conflict "
 
@@ -139,6 +353,11 @@ func (a AddOrUpdateChange) tryToResolveConflict(origErr error) error {
 		if len(recalcChanges) != 1 {
 			return fmt.Errorf("Expected exactly one change when recalculating conflicting change")
 		}
+		if recalcChanges[0].Op() == ctldiff.ChangeOpKeep {
+			// Resource already matches applied resource
+			// (e.g. previous update attempt was persisted)
+			return a.recordAppliedResource(latestExistingRes)
+		}
 		if recalcChanges[0].Op() != ctldiff.ChangeOpUpdate {
 			return fmt.Errorf("Expected recalculated change to be an update")
 		}
@@ -146,7 +365,7 @@ func (a AddOrUpdateChange) tryToResolveConflict(origErr error) error {
 			return fmt.Errorf(errMsgPrefix+"(approved diff no longer matches): %s", origErr)
 		}
 
-		updatedRes, err := a.identifiedResources.Update(recalcChanges[0].NewResource())
+		updatedRes, err := a.update(recalcChanges[0].NewResource())
 		if err != nil {
 			if errors.IsConflict(err) {
 				continue
@@ -160,6 +379,37 @@ func (a AddOrUpdateChange) tryToResolveConflict(origErr error) error {
 	return fmt.Errorf(errMsgPrefix+"(tried multiple times): %s", origErr)
 }
 
+// mergePatch only removes fields that were previously applied by kapp
+// so that fields set by the server or other controllers are preserved
+func (c AddOrUpdateChange) mergePatch(newRes ctlres.Resource) ([]byte, error) {
+	existingRes, err := c.identifiedResources.Get(c.change.ExistingResource())
+	if err != nil {
+		return nil, err
+	}
+
+	lastAppliedRes := c.changeFactory.NewResourceWithHistory(existingRes).RecordedLastAppliedResource()
+
+	return ctldiff.NewMergePatch(c.change.ExistingResource(), newRes, lastAppliedRes)
+}
+
+func (c AddOrUpdateChange) serverSideApplyConflictErr(origErr error) error {
+	var conflicts []string
+
+	if statusErr, ok := origErr.(errors.APIStatus); ok && statusErr.Status().Details != nil {
+		for _, cause := range statusErr.Status().Details.Causes {
+			conflicts = append(conflicts, fmt.Sprintf("- %s: %s", cause.Field, cause.Message))
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return origErr
+	}
+
+	return fmt.Errorf("Failed to apply resource %s (server-side) due to conflicts with other field managers "+
+		"(remove conflicting fields from configuration to let other managers own them):\n%s",
+		c.change.NewResource().Description(), strings.Join(conflicts, "\n"))
+}
+
 type SpecificResource interface {
 	IsDoneApplying() ctlresm.DoneApplyState
 }
@@ -0,0 +1,112 @@
+package clusterapply
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+type ApplyRetryOpts struct {
+	// MaxAttempts includes initial attempt (1 disables retries)
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+var (
+	// Errors returned by the API server when webhook may not
+	// be ready yet (e.g. deployed together with its configuration)
+	transientWebhookErrMsgs = []string{
+		"failed calling webhook",
+		"failed calling admission webhook",
+	}
+
+	// Errors that did not come from the API server (i.e. request
+	// may not have reached it); API server responses are not matched
+	// against these since they may include arbitrary messages
+	transientRequestErrMsgs = []string{
+		"connect: connection refused",
+		"connection reset by peer",
+		"i/o timeout",
+		"TLS handshake timeout",
+		// API server may not be serving recently created CRD yet
+		"no matches for kind",
+	}
+)
+
+// ApplyRetry retries API requests made while applying a change that
+// fail due to transient errors (API server timeouts and overload,
+// webhooks that are not ready, etc.) with exponential backoff until
+// retries for the change run out. Retries (MaxAttempts-1) are shared by
+// all requests made for the same change; successful requests do not
+// use them up.
+type ApplyRetry struct {
+	opts ApplyRetryOpts
+	desc string
+	ui   UI
+
+	interval time.Duration
+	retries  int
+}
+
+func NewApplyRetry(opts ApplyRetryOpts, desc string, ui UI) *ApplyRetry {
+	return &ApplyRetry{opts: opts, desc: desc, ui: ui, interval: opts.InitialInterval}
+}
+
+// Do calls given func until it succeeds or fails with non-transient error.
+// Returned bool indicates whether func was retried (hence previous attempt
+// may have been persisted by the API server even though it failed).
+func (r *ApplyRetry) Do(reqFunc func() error) (bool, error) {
+	var retried bool
+
+	for {
+		err := reqFunc()
+		if err == nil || r.retries >= r.opts.MaxAttempts-1 || !r.isTransientErr(err) {
+			return retried, err
+		}
+
+		r.retries++
+
+		r.ui.Notify([]string{fmt.Sprintf("%s: retrying in %s (retry %d of %d) after error: %s",
+			r.desc, r.interval, r.retries, r.opts.MaxAttempts-1, err)})
+
+		time.Sleep(r.interval)
+
+		retried = true
+
+		r.interval *= 2
+		if r.opts.MaxInterval > 0 && r.interval > r.opts.MaxInterval {
+			r.interval = r.opts.MaxInterval
+		}
+	}
+}
+
+func (r *ApplyRetry) isTransientErr(err error) bool {
+	if _, ok := err.(ctlres.ResourceTypesUnknownTypeErr); ok {
+		return true
+	}
+
+	errMsg := err.Error()
+
+	if statusErr, ok := err.(errors.APIStatus); ok {
+		if errors.IsServerTimeout(err) || errors.IsTimeout(err) || errors.IsTooManyRequests(err) ||
+			errors.IsServiceUnavailable(err) || errors.IsInternalError(err) || statusErr.Status().Code >= 500 {
+			return true
+		}
+		return r.containsAny(errMsg, transientWebhookErrMsgs)
+	}
+
+	return r.containsAny(errMsg, transientWebhookErrMsgs) || r.containsAny(errMsg, transientRequestErrMsgs)
+}
+
+func (*ApplyRetry) containsAny(str string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(str, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package clusterapply_test
+
+import (
+	"fmt"
+	"testing"
+
+	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestApplyRetry(t *testing.T) {
+	opts := ctlcap.ApplyRetryOpts{MaxAttempts: 3}
+
+	exs := []struct {
+		Err              error
+		ExpectedAttempts int
+	}{
+		{nil, 1},
+		{fmt.Errorf(`Internal error occurred: failed calling webhook "webhook.example.com": connect: connection refused`), 3},
+		{errors.NewServerTimeout(schema.GroupResource{Resource: "configmaps"}, "create", 1), 3},
+		{errors.NewTooManyRequests("too many requests", 1), 3},
+		{errors.NewBadRequest("bad request"), 1},
+		{fmt.Errorf(`admission webhook "webhook.example.com" denied the request`), 1},
+		{fmt.Errorf(`Post https://10.0.0.1:443/api/v1/namespaces: dial tcp 10.0.0.1:443: connect: connection refused`), 3},
+		{errors.NewBadRequest(`spec.url: Invalid value: "connect: connection refused"`), 1},
+	}
+
+	for _, ex := range exs {
+		var attempts int
+
+		retried, err := ctlcap.NewApplyRetry(opts, "update configmap/cm", &noopUI{}).Do(func() error {
+			attempts++
+			return ex.Err
+		})
+
+		if err != ex.Err {
+			t.Fatalf("Expected last error to be returned, but was: %#v", err)
+		}
+		if attempts != ex.ExpectedAttempts {
+			t.Fatalf("Expected %d attempts for error '%s', but was %d", ex.ExpectedAttempts, ex.Err, attempts)
+		}
+		if retried != (attempts > 1) {
+			t.Fatalf("Expected retried to be %t for error '%s'", attempts > 1, ex.Err)
+		}
+	}
+}
+
+func TestApplyRetrySharesRetriesBetweenRequests(t *testing.T) {
+	retry := ctlcap.NewApplyRetry(ctlcap.ApplyRetryOpts{MaxAttempts: 3}, "update configmap/cm", &noopUI{})
+	transientErr := errors.NewTooManyRequests("too many requests", 1)
+
+	var retries int
+
+	// Successful requests should not use up retries
+	for i := 0; i < 3; i++ {
+		retried, err := retry.Do(func() error { return nil })
+		if err != nil || retried {
+			t.Fatalf("Expected request to succeed without retry, but was: %t %s", retried, err)
+		}
+	}
+
+	var firstAttempts int
+
+	retried, err := retry.Do(func() error {
+		firstAttempts++
+		if firstAttempts == 1 {
+			return transientErr
+		}
+		return nil
+	})
+	if err != nil || !retried {
+		t.Fatalf("Expected request to succeed after retry, but was: %t %s", retried, err)
+	}
+	retries += firstAttempts - 1
+
+	var secondAttempts int
+
+	_, err = retry.Do(func() error {
+		secondAttempts++
+		return transientErr
+	})
+	if err != transientErr {
+		t.Fatalf("Expected request to fail, but was: %#v", err)
+	}
+	retries += secondAttempts - 1
+
+	if retries != 2 {
+		t.Fatalf("Expected 2 retries in total, but was %d", retries)
+	}
+}
+
+type noopUI struct{}
+
+func (noopUI) NotifySection(msg string, args ...interface{}) {}
+func (noopUI) Notify(msgs []string)                          {}
@@ -24,7 +24,9 @@ func NewApplyingChanges(numTotal int, opts ApplyingChangesOpts, clusterChangeFac
 	return &ApplyingChanges{numTotal, opts, map[*ctldgraph.Change]struct{}{}, clusterChangeFactory, ui}
 }
 
-func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange, error) {
+// Apply applies all given changes (even if some of them fail)
+// and returns changes that were successfully applied
+func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange, []error) {
 	var nonAppliedChanges []*ctldgraph.Change
 
 	for _, change := range allChanges {
@@ -40,9 +42,13 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 
 	c.ui.NotifySection("applying %d changes %s", len(nonAppliedChanges), c.stats())
 
+	type applyResult struct {
+		change WaitingChange
+		err    error
+	}
+
 	var wg sync.WaitGroup
-	var result []WaitingChange
-	applyErrCh := make(chan error, len(nonAppliedChanges))
+	applyResultCh := make(chan applyResult, len(nonAppliedChanges))
 
 	// Throttle number of changes are applied concurrently
 	// as it seems that client-go or api-server arent happy
@@ -55,6 +61,7 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 	for _, change := range nonAppliedChanges {
 		c.markApplied(change)
 		clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+		waitingChange := WaitingChange{change, clusterChange}
 
 		c.ui.Notify([]string{clusterChange.ApplyDescription()})
 		wg.Add(1)
@@ -66,22 +73,25 @@ func (c *ApplyingChanges) Apply(allChanges []*ctldgraph.Change) ([]WaitingChange
 			defer applyThrottle.Done()
 
 			err := clusterChange.Apply()
-			applyErrCh <- err
+			applyResultCh <- applyResult{waitingChange, err}
 		}()
-
-		result = append(result, WaitingChange{change, clusterChange})
 	}
 
 	wg.Wait()
-	close(applyErrCh)
+	close(applyResultCh)
+
+	var result []WaitingChange
+	var errs []error
 
-	for err := range applyErrCh {
-		if err != nil {
-			return nil, err
+	for applyResult := range applyResultCh {
+		if applyResult.err != nil {
+			errs = append(errs, applyResult.err)
+		} else {
+			result = append(result, applyResult.change)
 		}
 	}
 
-	return result, nil
+	return result, errs
 }
 
 func (c *ApplyingChanges) Complete() error {
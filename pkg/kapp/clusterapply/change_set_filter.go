@@ -0,0 +1,177 @@
+package clusterapply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+// ChangeSetFilter selects changes based on their operations
+// and resources (e.g. to only apply additions and updates)
+type ChangeSetFilter struct {
+	And      []ChangeSetFilter
+	Or       []ChangeSetFilter
+	Not      *ChangeSetFilter
+	Ops      []ClusterChangeApplyOp
+	WaitOps  []ClusterChangeWaitOp
+	Resource *ctlres.ResourceFilter
+}
+
+func NewChangeSetFilterFromString(data string) (*ChangeSetFilter, error) {
+	var filter ChangeSetFilter
+
+	// Reject unknown keys so that typos do not silently
+	// result in a filter that matches different changes
+	dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	err = filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &filter, nil
+}
+
+// Validate checks that each filter (including nested ones) specifies
+// exactly one of its keys, since only one of them would be used for matching
+func (f ChangeSetFilter) Validate() error {
+	var keys []string
+
+	if len(f.And) > 0 {
+		keys = append(keys, "and")
+	}
+	if len(f.Or) > 0 {
+		keys = append(keys, "or")
+	}
+	if f.Not != nil {
+		keys = append(keys, "not")
+	}
+	if len(f.Ops) > 0 {
+		keys = append(keys, "ops")
+	}
+	if len(f.WaitOps) > 0 {
+		keys = append(keys, "waitOps")
+	}
+	if f.Resource != nil {
+		keys = append(keys, "resource")
+	}
+
+	if len(keys) != 1 {
+		return fmt.Errorf("Expected filter to specify exactly one non-empty key "+
+			"(one of: and, or, not, ops, waitOps, resource), but found: %s", f.keysStr(keys))
+	}
+
+	for _, f2 := range f.And {
+		err := f2.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, f2 := range f.Or {
+		err := f2.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.Not != nil {
+		err := f.Not.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, op := range f.Ops {
+		switch op {
+		case ClusterChangeApplyOpAdd, ClusterChangeApplyOpDelete,
+			ClusterChangeApplyOpUpdate, ClusterChangeApplyOpNoop:
+		default:
+			return fmt.Errorf("Unknown apply op '%s' (expected one of: add, delete, update, noop)", op)
+		}
+	}
+
+	for _, op := range f.WaitOps {
+		switch op {
+		case ClusterChangeWaitOpOK, ClusterChangeWaitOpDelete, ClusterChangeWaitOpNoop:
+		default:
+			return fmt.Errorf("Unknown wait op '%s' (expected one of: ok, delete, noop)", op)
+		}
+	}
+
+	return nil
+}
+
+func (ChangeSetFilter) keysStr(keys []string) string {
+	if len(keys) == 0 {
+		return "none"
+	}
+	return strings.Join(keys, ", ")
+}
+
+func (f ChangeSetFilter) Apply(changeViews []ChangeView) []ChangeView {
+	var result []ChangeView
+	for _, view := range changeViews {
+		if f.Matches(view) {
+			result = append(result, view)
+		}
+	}
+	return result
+}
+
+func (f ChangeSetFilter) Matches(change ChangeView) bool {
+	if len(f.And) > 0 {
+		for _, f2 := range f.And {
+			if !f2.Matches(change) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if len(f.Or) > 0 {
+		for _, f2 := range f.Or {
+			if f2.Matches(change) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.Not != nil {
+		return !f.Not.Matches(change)
+	}
+
+	if len(f.Ops) > 0 {
+		for _, op := range f.Ops {
+			if op == change.ApplyOp() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(f.WaitOps) > 0 {
+		for _, op := range f.WaitOps {
+			if op == change.WaitOp() {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.Resource != nil {
+		return f.Resource.Matches(change.Resource())
+	}
+
+	return false
+}
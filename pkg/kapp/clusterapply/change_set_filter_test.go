@@ -0,0 +1,76 @@
+package clusterapply_test
+
+import (
+	"testing"
+
+	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestChangeSetFilter(t *testing.T) {
+	filter, err := ctlcap.NewChangeSetFilterFromString(
+		`{"and":[{"ops":["add","update"]},{"not":{"resource":{"kinds":["Secret"]}}}]}`)
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	exs := []struct {
+		View     changeView
+		Expected bool
+	}{
+		{changeView{"ConfigMap", ctlcap.ClusterChangeApplyOpAdd}, true},
+		{changeView{"ConfigMap", ctlcap.ClusterChangeApplyOpUpdate}, true},
+		{changeView{"ConfigMap", ctlcap.ClusterChangeApplyOpDelete}, false},
+		{changeView{"Secret", ctlcap.ClusterChangeApplyOpAdd}, false},
+	}
+
+	for _, ex := range exs {
+		if filter.Matches(ex.View) != ex.Expected {
+			t.Fatalf("Expected filter to return %t for %s %s", ex.Expected, ex.View.applyOp, ex.View.kind)
+		}
+	}
+}
+
+func TestChangeSetFilterInvalid(t *testing.T) {
+	exs := []struct {
+		Data        string
+		ExpectedErr string
+	}{
+		{`{"op":["add"]}`, `json: unknown field "op"`},
+		{`{"not":{"resource":{"kind":["Secret"]}}}`, `json: unknown field "kind"`},
+		{`{"or":[{"ops":["create"]}]}`, "Unknown apply op 'create' (expected one of: add, delete, update, noop)"},
+		{`{"waitOps":["done"]}`, "Unknown wait op 'done' (expected one of: ok, delete, noop)"},
+		{`{}`, "Expected filter to specify exactly one non-empty key (one of: and, or, not, ops, waitOps, resource), but found: none"},
+		{`{"ops":[]}`, "Expected filter to specify exactly one non-empty key (one of: and, or, not, ops, waitOps, resource), but found: none"},
+		{`{"ops":["add"],"resource":{"kinds":["Secret"]}}`, "Expected filter to specify exactly one non-empty key (one of: and, or, not, ops, waitOps, resource), but found: ops, resource"},
+		{`{"and":[{"ops":["add"]},{}]}`, "Expected filter to specify exactly one non-empty key (one of: and, or, not, ops, waitOps, resource), but found: none"},
+	}
+
+	for _, ex := range exs {
+		_, err := ctlcap.NewChangeSetFilterFromString(ex.Data)
+		if err == nil {
+			t.Fatalf("Expected err for %s", ex.Data)
+		}
+		if err.Error() != ex.ExpectedErr {
+			t.Fatalf("Expected err for %s to be '%s', but was '%s'", ex.Data, ex.ExpectedErr, err)
+		}
+	}
+}
+
+type changeView struct {
+	kind    string
+	applyOp ctlcap.ClusterChangeApplyOp
+}
+
+var _ ctlcap.ChangeView = changeView{}
+
+func (v changeView) Resource() ctlres.Resource {
+	return ctlres.MustNewResourceFromBytes([]byte("kind: " + v.kind + "\nmetadata:\n  name: res\n"))
+}
+
+func (v changeView) ExistingResource() ctlres.Resource    { return nil }
+func (v changeView) ApplyOp() ctlcap.ClusterChangeApplyOp { return v.applyOp }
+func (v changeView) WaitOp() ctlcap.ClusterChangeWaitOp   { return ctlcap.ClusterChangeWaitOpNoop }
+func (v changeView) TextDiff() ctldiff.TextDiff           { return ctldiff.TextDiff{} }
+func (v changeView) OpsDiff() ctldiff.OpsDiff             { return ctldiff.OpsDiff{} }
@@ -1,28 +1,98 @@
 package clusterapply
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/cppforlife/go-cli-ui/ui"
+	"github.com/ghodss/yaml"
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+type ChangeSetViewFormat string
+
+const (
+	ChangeSetViewFormatText      ChangeSetViewFormat = "text"
+	ChangeSetViewFormatJSON      ChangeSetViewFormat = "json"
+	ChangeSetViewFormatUnified   ChangeSetViewFormat = "unified"
+	ChangeSetViewFormatYAMLPatch ChangeSetViewFormat = "yaml-patch"
+)
+
+var (
+	ChangeSetViewFormats = []ChangeSetViewFormat{
+		ChangeSetViewFormatText,
+		ChangeSetViewFormatJSON,
+		ChangeSetViewFormatUnified,
+		ChangeSetViewFormatYAMLPatch,
+	}
 )
 
 type ChangeSetViewOpts struct {
 	Summary bool
 	Changes bool
+	// Format other than text only prints changes in machine readable form
+	Format ChangeSetViewFormat
 	ctldiff.TextDiffViewOpts
 }
 
 type ChangeSetView struct {
 	changeViews []ChangeView
 	opts        ChangeSetViewOpts
-
-	changesView *ChangesView
 }
 
 func NewChangeSetView(changeViews []ChangeView, opts ChangeSetViewOpts) *ChangeSetView {
-	return &ChangeSetView{changeViews, opts, nil}
+	return &ChangeSetView{changeViews, opts}
 }
 
-func (v *ChangeSetView) Print(ui ui.UI) {
+func (v *ChangeSetView) Print(ui ui.UI) error {
+	switch v.opts.Format {
+	case ChangeSetViewFormatJSON:
+		jsonBytes, err := v.jsonBytes()
+		if err != nil {
+			return err
+		}
+		ui.PrintBlock(jsonBytes)
+		return nil
+
+	case ChangeSetViewFormatUnified:
+		for _, view := range v.sortedChangeViews() {
+			if view.ApplyOp() == ClusterChangeApplyOpNoop {
+				continue
+			}
+			fromName, toName := v.resourceKey(view), v.resourceKey(view)
+			switch view.ApplyOp() {
+			case ClusterChangeApplyOpAdd:
+				fromName = "/dev/null"
+			case ClusterChangeApplyOpDelete:
+				toName = "/dev/null"
+			}
+			unifiedDiffView := ctldiff.NewUnifiedDiffView(view.TextDiff(), v.opts.TextDiffViewOpts)
+			ui.PrintBlock([]byte(unifiedDiffView.String(fromName, toName)))
+		}
+		return nil
+
+	case ChangeSetViewFormatYAMLPatch:
+		for _, view := range v.sortedChangeViews() {
+			if view.ApplyOp() == ClusterChangeApplyOpNoop {
+				continue
+			}
+			opDefs, err := view.OpsDiff().Definitions()
+			if err != nil {
+				return fmt.Errorf("Building ops diff for '%s': %s", v.resourceKey(view), err)
+			}
+			opsBytes, err := yamlv2.Marshal(opDefs)
+			if err != nil {
+				return fmt.Errorf("Encoding ops diff for '%s': %s", v.resourceKey(view), err)
+			}
+			ui.PrintBlock([]byte(fmt.Sprintf("---\n# %s %s\n%s",
+				applyOpCodeUI[view.ApplyOp()], v.resourceKey(view), opsBytes)))
+		}
+		return nil
+	}
+
 	if v.opts.Changes {
 		for _, view := range v.changeViews {
 			textDiffView := ctldiff.NewTextDiffView(view.TextDiff(), v.opts.TextDiffViewOpts)
@@ -31,13 +101,71 @@ func (v *ChangeSetView) Print(ui ui.UI) {
 		}
 	}
 
-	v.changesView = &ChangesView{ChangeViews: v.changeViews, Sort: true}
-
 	if v.opts.Summary {
-		v.changesView.Print(ui)
+		changesView := &ChangesView{ChangeViews: v.changeViews, Sort: true}
+		changesView.Print(ui)
 	}
+
+	return nil
 }
 
 func (v *ChangeSetView) Summary() string {
-	return v.changesView.Summary() // assumes Print was used before
+	countsView := NewChangesCountsView()
+	for _, view := range v.changeViews {
+		countsView.Add(view.ApplyOp(), view.WaitOp())
+	}
+	return countsView.String()
+}
+
+// HasChanges returns true if at least one resource is going to be changed
+func (v *ChangeSetView) HasChanges() bool {
+	for _, view := range v.changeViews {
+		if view.ApplyOp() != ClusterChangeApplyOpNoop {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *ChangeSetView) jsonBytes() ([]byte, error) {
+	changes := []interface{}{}
+
+	for _, view := range v.sortedChangeViews() {
+		opDefs, err := view.OpsDiff().Definitions()
+		if err != nil {
+			return nil, fmt.Errorf("Building ops diff for '%s': %s", v.resourceKey(view), err)
+		}
+
+		changes = append(changes, map[string]interface{}{
+			"op":  applyOpCodeUI[view.ApplyOp()],
+			"key": v.resourceKey(view),
+			"ops": opDefs,
+		})
+	}
+
+	// Ops values are decoded from YAML and may contain non-string map keys,
+	// hence go through YAML first
+	yamlBytes, err := yamlv2.Marshal(map[string]interface{}{"changes": changes})
+	if err != nil {
+		return nil, fmt.Errorf("Encoding changes as YAML: %s", err)
+	}
+
+	jsonBytes, err := yaml.YAMLToJSON(yamlBytes)
+	if err != nil {
+		return nil, fmt.Errorf("Converting changes to JSON: %s", err)
+	}
+
+	return append(jsonBytes, '\n'), nil
+}
+
+func (v *ChangeSetView) sortedChangeViews() []ChangeView {
+	views := append([]ChangeView{}, v.changeViews...)
+	sort.SliceStable(views, func(i, j int) bool {
+		return strings.Compare(v.resourceKey(views[i]), v.resourceKey(views[j])) < 0
+	})
+	return views
+}
+
+func (v *ChangeSetView) resourceKey(view ChangeView) string {
+	return ctlres.NewUniqueResourceKey(view.Resource()).String()
 }
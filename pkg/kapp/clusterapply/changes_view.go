@@ -19,6 +19,7 @@ type ChangeView interface {
 	ApplyOp() ClusterChangeApplyOp
 	WaitOp() ClusterChangeWaitOp
 	TextDiff() ctldiff.TextDiff
+	OpsDiff() ctldiff.OpsDiff
 }
 
 type ChangesView struct {
@@ -36,6 +36,8 @@ type ClusterChangeOpts struct {
 	WaitIgnored  bool
 
 	AddOrUpdateChangeOpts
+	DeleteChangeOpts
+	ApplyRetryOpts
 }
 
 type ClusterChange struct {
@@ -73,6 +75,9 @@ func (c *ClusterChange) ApplyOp() ClusterChangeApplyOp {
 	case ctldiff.ChangeOpDelete:
 		return ClusterChangeApplyOpDelete
 	case ctldiff.ChangeOpUpdate:
+		if c.skipsUpdate() {
+			return ClusterChangeApplyOpNoop
+		}
 		return ClusterChangeApplyOpUpdate
 	case ctldiff.ChangeOpKeep:
 		return ClusterChangeApplyOpNoop
@@ -97,7 +102,13 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 	}
 
 	switch c.change.Op() {
-	case ctldiff.ChangeOpAdd, ctldiff.ChangeOpUpdate:
+	case ctldiff.ChangeOpAdd:
+		return ClusterChangeWaitOpOK
+
+	case ctldiff.ChangeOpUpdate:
+		if c.skipsUpdate() {
+			return ClusterChangeWaitOpNoop
+		}
 		return ClusterChangeWaitOpOK
 
 	case ctldiff.ChangeOpDelete:
@@ -130,6 +141,12 @@ func (c *ClusterChange) WaitOp() ClusterChangeWaitOp {
 	}
 }
 
+// skipsUpdate indicates that existing resource should be left as is
+// since it's only meant to be created once
+func (c *ClusterChange) skipsUpdate() bool {
+	return c.opts.createStrategy(c.change.NewResource()) == createStrategySkipIfExistsAnnValue
+}
+
 func (c *ClusterChange) MarkNeedsWaiting() { c.markedNeedsWaiting = true }
 
 func (c *ClusterChange) Apply() error {
@@ -137,12 +154,43 @@ func (c *ClusterChange) Apply() error {
 
 	switch op {
 	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate:
-		return c.applyErr(AddOrUpdateChange{
-			c.change, c.identifiedResources, c.changeFactory,
-			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts}.Apply())
+		return c.applyErr(c.addOrUpdateChange().Apply())
+
+	case ClusterChangeApplyOpDelete:
+		return c.applyErr(c.deleteChange().Apply())
+
+	case ClusterChangeApplyOpNoop:
+		return nil
+
+	default:
+		return fmt.Errorf("Unknown change apply operation: %s", op)
+	}
+}
+
+// addOrUpdateChange and deleteChange return changes that retry
+// their API requests (with retries shared between requests of the change)
+func (c *ClusterChange) addOrUpdateChange() AddOrUpdateChange {
+	return AddOrUpdateChange{c.change, c.identifiedResources, c.changeFactory,
+		c.changeSetFactory, c.opts.AddOrUpdateChangeOpts, c.applyRetry()}
+}
+
+func (c *ClusterChange) deleteChange() DeleteChange {
+	return DeleteChange{c.change, c.identifiedResources, c.opts.DeleteChangeOpts, c.applyRetry()}
+}
+
+func (c *ClusterChange) applyRetry() *ApplyRetry {
+	return NewApplyRetry(c.opts.ApplyRetryOpts, c.ApplyDescription(), c.ui)
+}
+
+func (c *ClusterChange) DryRun() error {
+	op := c.ApplyOp()
+
+	switch op {
+	case ClusterChangeApplyOpAdd, ClusterChangeApplyOpUpdate:
+		return c.addOrUpdateChange().DryRun()
 
 	case ClusterChangeApplyOpDelete:
-		return c.applyErr(DeleteChange{c.change, c.identifiedResources}.Apply())
+		return c.deleteChange().DryRun()
 
 	case ClusterChangeApplyOpNoop:
 		return nil
@@ -163,12 +211,10 @@ func (c *ClusterChange) isDoneApplying() (ctlresm.DoneApplyState, []string, erro
 
 	switch op {
 	case ClusterChangeWaitOpOK:
-		return AddOrUpdateChange{
-			c.change, c.identifiedResources, c.changeFactory,
-			c.changeSetFactory, c.opts.AddOrUpdateChangeOpts}.IsDoneApplying()
+		return c.addOrUpdateChange().IsDoneApplying()
 
 	case ClusterChangeWaitOpDelete:
-		return DeleteChange{c.change, c.identifiedResources}.IsDoneApplying()
+		return c.deleteChange().IsDoneApplying()
 
 	case ClusterChangeWaitOpNoop:
 		return ctlresm.DoneApplyState{Done: true, Successful: true}, nil, nil
@@ -190,6 +236,7 @@ func (c *ClusterChange) Resource() ctlres.Resource         { return c.change.New
 func (c *ClusterChange) ExistingResource() ctlres.Resource { return c.change.ExistingResource() }
 
 func (c *ClusterChange) TextDiff() ctldiff.TextDiff { return c.change.TextDiff() }
+func (c *ClusterChange) OpsDiff() ctldiff.OpsDiff   { return c.change.OpsDiff() }
 
 func (c *ClusterChange) applyErr(err error) error {
 	if err == nil {
@@ -2,14 +2,27 @@ package clusterapply
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
 	ctldgraph "github.com/k14s/kapp/pkg/kapp/diffgraph"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	ctlresm "github.com/k14s/kapp/pkg/kapp/resourcesmisc"
+	"github.com/k14s/kapp/pkg/kapp/util"
 )
 
 type ClusterChangeSetOpts struct {
 	ApplyingChangesOpts
 	WaitingChangesOpts
+
+	// Filter excludes changes from being applied (and waited for)
+	Filter *ChangeSetFilter
+	// DryRunServer checks all changes with the server before applying any of them
+	DryRunServer bool
+	// ContinueOnError keeps applying changes that do not depend on failed changes
+	ContinueOnError bool
 }
 
 type ClusterChangeSet struct {
@@ -30,6 +43,13 @@ func (c ClusterChangeSet) Calculate() ([]*ClusterChange, *ctldgraph.ChangeGraph,
 
 	for _, change := range c.changes {
 		clusterChange := c.clusterChangeFactory.NewClusterChange(change)
+
+		// Filter out changes before graph is built so that
+		// remaining changes do not end up waiting for them
+		if c.opts.Filter != nil && !c.opts.Filter.Matches(clusterChange) {
+			continue
+		}
+
 		wrappedClusterChanges = append(wrappedClusterChanges, wrappedClusterChange{clusterChange})
 	}
 
@@ -77,6 +97,13 @@ func (c ClusterChangeSet) markChangesToWait(change *ctldgraph.Change) bool {
 }
 
 func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
+	if c.opts.DryRunServer {
+		err := c.dryRun(changesGraph)
+		if err != nil {
+			return err
+		}
+	}
+
 	expectedNumChanges := len(changesGraph.All())
 
 	blockedChanges := ctldgraph.NewBlockedChanges(changesGraph)
@@ -84,15 +111,25 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 		expectedNumChanges, c.opts.ApplyingChangesOpts, c.clusterChangeFactory, c.ui)
 	waitingChanges := NewWaitingChanges(expectedNumChanges, c.opts.WaitingChangesOpts, c.ui)
 
+	var failedErrs []error
+
 	for {
-		appliedChanges, err := applyingChanges.Apply(blockedChanges.Unblocked())
-		if err != nil {
-			return err
-		}
+		appliedChanges, errs := applyingChanges.Apply(blockedChanges.Unblocked())
 
 		waitingChanges.Track(appliedChanges)
 
+		if len(errs) > 0 {
+			if !c.opts.ContinueOnError {
+				return c.changesErr(errs)
+			}
+			failedErrs = append(failedErrs, errs...)
+		}
+
 		if waitingChanges.IsEmpty() {
+			if len(failedErrs) > 0 {
+				return c.failedChangesErr(failedErrs, blockedChanges)
+			}
+
 			err := applyingChanges.Complete()
 			if err != nil {
 				c.ui.Notify([]string{fmt.Sprintf("Blocked changes:\n%s\n", blockedChanges.WhyBlocked(blockedChanges.Blocked()))})
@@ -102,9 +139,13 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 			return waitingChanges.Complete()
 		}
 
-		doneChanges, err := waitingChanges.WaitForAny()
-		if err != nil {
-			return err
+		doneChanges, errs := waitingChanges.WaitForAny()
+
+		if len(errs) > 0 {
+			if !c.opts.ContinueOnError {
+				return c.changesErr(errs)
+			}
+			failedErrs = append(failedErrs, errs...)
 		}
 
 		for _, change := range doneChanges {
@@ -113,6 +154,120 @@ func (c ClusterChangeSet) Apply(changesGraph *ctldgraph.ChangeGraph) error {
 	}
 }
 
+func (c ClusterChangeSet) changesErr(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, "- "+err.Error())
+	}
+	sort.Strings(msgs)
+
+	return fmt.Errorf("Failed %d changes:\n%s", len(errs), strings.Join(msgs, "\n"))
+}
+
+// failedChangesErr reports all failed changes together with
+// changes that were not applied since they depend on failed ones
+func (c ClusterChangeSet) failedChangesErr(errs []error, blockedChanges *ctldgraph.BlockedChanges) error {
+	var msgs []string
+	for _, err := range errs {
+		msgs = append(msgs, "- "+err.Error())
+	}
+	sort.Strings(msgs)
+
+	blocked := blockedChanges.Blocked()
+
+	if len(blocked) > 0 {
+		c.ui.Notify([]string{fmt.Sprintf("Blocked changes:\n%s\n", blockedChanges.WhyBlocked(blocked))})
+	}
+
+	return fmt.Errorf("Failed %d changes (%d changes were not applied since they depend on failed changes):\n%s",
+		len(errs), len(blocked), strings.Join(msgs, "\n"))
+}
+
+// dryRun submits all changes with server-side dry run enabled
+// and reports all of the errors (e.g. admission webhook rejections)
+func (c ClusterChangeSet) dryRun(changesGraph *ctldgraph.ChangeGraph) error {
+	var changes []*ctldgraph.Change
+	var numSkipped int
+
+	for _, change := range changesGraph.All() {
+		if c.dependsOnNewDefinitions(change) {
+			numSkipped++
+		} else {
+			changes = append(changes, change)
+		}
+	}
+
+	c.ui.NotifySection("dry running %d changes (skipped: %d)", len(changes), numSkipped)
+
+	var wg sync.WaitGroup
+	dryRunErrCh := make(chan error, len(changes))
+	dryRunThrottle := util.NewThrottle(c.opts.ApplyingChangesOpts.Concurrency)
+
+	for _, change := range changes {
+		clusterChange := change.Change.(wrappedClusterChange).ClusterChange
+		wg.Add(1)
+
+		go func() {
+			defer func() { wg.Done() }()
+
+			dryRunThrottle.Take()
+			defer dryRunThrottle.Done()
+
+			dryRunErrCh <- clusterChange.DryRun()
+		}()
+	}
+
+	wg.Wait()
+	close(dryRunErrCh)
+
+	var errMsgs []string
+
+	for err := range dryRunErrCh {
+		if err != nil {
+			errMsgs = append(errMsgs, "- "+err.Error())
+		}
+	}
+
+	if len(errMsgs) > 0 {
+		sort.Strings(errMsgs)
+		return fmt.Errorf("Server-side dry run failed for %d changes (no changes were applied):\n%s",
+			len(errMsgs), strings.Join(errMsgs, "\n"))
+	}
+
+	c.ui.NotifySection("dry running complete")
+
+	return nil
+}
+
+// dependsOnNewDefinitions returns true if change cannot be checked via dry run
+// because its namespace or CRD is created as part of the same change set
+func (c ClusterChangeSet) dependsOnNewDefinitions(change *ctldgraph.Change) bool {
+	res := change.Change.Resource()
+
+	for _, depChange := range change.WaitingFor {
+		if depChange.Change.(wrappedClusterChange).ApplyOp() != ClusterChangeApplyOpAdd {
+			continue
+		}
+
+		depRes := depChange.Change.Resource()
+		nsMatcher := ctlres.APIGroupKindMatcher{Kind: "Namespace"}
+
+		if nsMatcher.Matches(depRes) && depRes.Name() == res.Namespace() {
+			return true
+		}
+
+		if crd := ctlresm.NewApiExtensionsVxCRD(depRes); crd != nil && crd.DefinesResource(res) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func ClusterChangesAsChangeViews(changes []*ClusterChange) []ChangeView {
 	var result []ChangeView
 	for _, change := range changes {
@@ -3,10 +3,14 @@ package clusterapply
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 	ctlresm "github.com/k14s/kapp/pkg/kapp/resourcesmisc"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -16,11 +20,60 @@ const (
 	deleteStrategyOrphanAnnKey  = "orphan"
 
 	orphanedAnnKey = "kapp.k14s.io/orphaned"
+
+	deletePropagationAnnKey             = "kapp.k14s.io/delete-propagation"
+	deletePropagationBackgroundAnnValue = "background"
+	deletePropagationForegroundAnnValue = "foreground"
+	deletePropagationOrphanAnnValue     = "orphan"
 )
 
+type DeleteChangeOpts struct {
+	DefaultDeletePropagation string
+
+	// Finalizers are removed from resources that stay
+	// in deleting state for longer than this duration (0 disables)
+	RemoveFinalizersAfter time.Duration
+}
+
+func (o DeleteChangeOpts) propagationPolicy(res ctlres.Resource) (metav1.DeletionPropagation, error) {
+	val, found := res.Annotations()[deletePropagationAnnKey]
+	if !found {
+		val = o.DefaultDeletePropagation
+	}
+
+	switch val {
+	case "", deletePropagationBackgroundAnnValue:
+		return metav1.DeletePropagationBackground, nil
+	case deletePropagationForegroundAnnValue:
+		return metav1.DeletePropagationForeground, nil
+	case deletePropagationOrphanAnnValue:
+		return metav1.DeletePropagationOrphan, nil
+	default:
+		return "", fmt.Errorf("Unknown delete propagation: %s", val)
+	}
+}
+
 type DeleteChange struct {
 	change              ctldiff.Change
 	identifiedResources ctlres.IdentifiedResources
+	opts                DeleteChangeOpts
+	retry               *ApplyRetry
+}
+
+// DryRun checks with the server whether resource would be deleted without deleting it
+func (c DeleteChange) DryRun() error {
+	res := c.change.ExistingResource()
+
+	switch res.Annotations()[deleteStrategyAnnKey] {
+	case deleteStrategyOrphanAnnKey:
+		return nil // resource is only annotated
+	default:
+		propagationPolicy, err := c.opts.propagationPolicy(res)
+		if err != nil {
+			return err
+		}
+		return c.identifiedResources.DryRunDelete(res, ctlres.DeleteOpts{PropagationPolicy: propagationPolicy})
+	}
 }
 
 func (c DeleteChange) Apply() error {
@@ -43,15 +96,24 @@ func (c DeleteChange) Apply() error {
 			return err
 		}
 
-		_, err = c.identifiedResources.Patch(res, types.MergePatchType, patchJSON)
+		_, err = c.retry.Do(func() error {
+			_, err := c.identifiedResources.Patch(res, types.MergePatchType, patchJSON)
+			return err
+		})
 		if err != nil {
 			return err
 		}
 
 	case deleteStrategyDefaultAnnKey:
-		// TODO should we be configuring default garbage collection policy to background?
-		// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/
-		err := c.identifiedResources.Delete(res)
+		propagationPolicy, err := c.opts.propagationPolicy(res)
+		if err != nil {
+			return err
+		}
+
+		// Resource that was already deleted by previous attempt is not found (not an error)
+		_, err = c.retry.Do(func() error {
+			return c.identifiedResources.Delete(res, ctlres.DeleteOpts{PropagationPolicy: propagationPolicy})
+		})
 		if err != nil {
 			return err
 		}
@@ -78,5 +140,58 @@ func (c DeleteChange) IsDoneApplying() (ctlresm.DoneApplyState, []string, error)
 		return ctlresm.DoneApplyState{}, nil, err
 	}
 
+	if exists && c.opts.RemoveFinalizersAfter > 0 {
+		descMsgs, err := c.removeStuckFinalizers(res)
+		return ctlresm.DoneApplyState{Done: false}, descMsgs, err
+	}
+
 	return ctlresm.DoneApplyState{Done: !exists, Successful: true}, nil, nil
 }
+
+// removeStuckFinalizers forcefully removes metadata.finalizers from resource that
+// has been deleting for too long (e.g. because its controller is gone).
+// Namespace spec.finalizers are not removed (they require finalize subresource)
+func (c DeleteChange) removeStuckFinalizers(res ctlres.Resource) ([]string, error) {
+	latestRes, err := c.identifiedResources.Get(res)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	finalizers := latestRes.Finalizers()
+
+	if !latestRes.IsDeleting() || len(finalizers) == 0 {
+		return nil, nil
+	}
+
+	deletingDur := time.Now().Sub(latestRes.DeletingSince())
+	if deletingDur < c.opts.RemoveFinalizersAfter {
+		return nil, nil
+	}
+
+	mergePatch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers": nil, // null removes key in merge patch
+		},
+	}
+
+	patchJSON, err := json.Marshal(mergePatch)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = c.identifiedResources.Patch(latestRes, types.MergePatchType, patchJSON)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Removing finalizers from resource %s: %s", latestRes.Description(), err)
+	}
+
+	msg := fmt.Sprintf("Removed finalizers (%s) from resource %s deleting for %s",
+		strings.Join(finalizers, ", "), latestRes.Description(), deletingDur.Round(time.Second))
+
+	return []string{uiWaitMsgPrefix + msg}, nil
+}
@@ -37,7 +37,9 @@ func (c *WaitingChanges) IsEmpty() bool {
 	return len(c.trackedChanges) == 0
 }
 
-func (c *WaitingChanges) WaitForAny() ([]WaitingChange, error) {
+// WaitForAny waits until at least one of tracked changes is done applying.
+// Changes that finish unsuccessfully are returned as errors and no longer tracked.
+func (c *WaitingChanges) WaitForAny() ([]WaitingChange, []error) {
 	startTime := time.Now()
 
 	for {
@@ -45,6 +47,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, error) {
 
 		var newInProgressChanges []WaitingChange
 		var doneChanges []WaitingChange
+		var errs []error
 
 		for _, change := range c.trackedChanges {
 			desc := fmt.Sprintf("waiting on %s", change.Cluster.WaitDescription())
@@ -53,7 +56,8 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, error) {
 			c.ui.Notify(descMsgs)
 
 			if err != nil {
-				return nil, fmt.Errorf("%s: errored: %s", desc, err)
+				errs = append(errs, fmt.Errorf("%s: errored: %s", desc, err))
+				continue
 			}
 			if state.Done {
 				c.numWaited += 1
@@ -68,7 +72,7 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, error) {
 				if len(state.Message) > 0 {
 					msg += " (" + state.Message + ")"
 				}
-				return nil, fmt.Errorf("%s: finished unsuccessfully%s", desc, msg)
+				errs = append(errs, fmt.Errorf("%s: finished unsuccessfully%s", desc, msg))
 
 			case state.Done && state.Successful:
 				doneChanges = append(doneChanges, change)
@@ -77,12 +81,17 @@ func (c *WaitingChanges) WaitForAny() ([]WaitingChange, error) {
 
 		c.trackedChanges = newInProgressChanges
 
-		if len(c.trackedChanges) == 0 || len(doneChanges) > 0 {
-			return doneChanges, nil
+		if len(c.trackedChanges) == 0 || len(doneChanges) > 0 || len(errs) > 0 {
+			return doneChanges, errs
 		}
 
 		if time.Now().Sub(startTime) > c.opts.Timeout {
-			return nil, fmt.Errorf("timed out waiting after %s", c.opts.Timeout)
+			for _, change := range c.trackedChanges {
+				errs = append(errs, fmt.Errorf("waiting on %s: timed out waiting after %s",
+					change.Cluster.WaitDescription(), c.opts.Timeout))
+			}
+			c.trackedChanges = nil
+			return nil, errs
 		}
 
 		time.Sleep(c.opts.CheckInterval)
@@ -0,0 +1,312 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type AdoptOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags           AppFlags
+	FileFlags          cmdtools.FileFlags
+	ResourceTypesFlags ResourceTypesFlags
+	LabelFlags         LabelFlags
+	ChangeMetaFlags    ChangeMetaFlags
+
+	Selector      string
+	AllNamespaces bool
+	LockTTL       time.Duration
+}
+
+// controllerManagedFilter matches resources that are created and
+// deleted by the cluster (typically without owner references,
+// e.g. Endpoints copy labels of their Service) hence not adopted
+var controllerManagedFilter = ctlres.ResourceFilter{
+	Kinds: []string{"Endpoints", "EndpointSlice", "Event"},
+}
+
+func NewAdoptOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *AdoptOptions {
+	return &AdoptOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewAdoptCmd(o *AdoptOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "adopt",
+		Short: "Adopt existing cluster resources into app",
+		Long: "Adopt existing cluster resources into app by labeling them (resources owned by other apps are skipped)." +
+			" Resources are not otherwise modified.",
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Adopt resources labeled with 'app=app1' (in app namespace) into app 'app1'
+  kapp app adopt -a app1 --selector app=app1
+
+  # Adopt resources labeled with 'app=app1' across all namespaces
+  # (including cluster scoped resources) into app 'app1'
+  kapp app adopt -a app1 --selector app=app1 --all-namespaces
+
+  # Adopt resources specified in config files (e.g. previously applied via kubectl)
+  kapp app adopt -a app1 -f config/`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.FileFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	o.LabelFlags.Set(cmd)
+	o.ChangeMetaFlags.Set(cmd)
+	cmd.Flags().StringVar(&o.Selector, "selector", "", "Set label selector to find resources to adopt (e.g. app=app1)")
+	cmd.Flags().BoolVar(&o.AllNamespaces, "all-namespaces", false, "Find resources to adopt via --selector across all namespaces (including cluster scoped resources)")
+	cmd.Flags().DurationVar(&o.LockTTL, "lock-ttl", 30*time.Minute, "Maximum amount of time app lock is held before it's considered stale")
+	return cmd
+}
+
+func (o *AdoptOptions) Run() error {
+	if (len(o.Selector) > 0) == (len(o.FileFlags.Files) > 0) {
+		return fmt.Errorf("Expected either --selector or --file to be specified")
+	}
+	if o.AllNamespaces && len(o.Selector) == 0 {
+		return fmt.Errorf("Expected --all-namespaces to be used with --selector")
+	}
+
+	app, supportObjs, err := AppFactory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	appLabels, err := o.LabelFlags.AsMap()
+	if err != nil {
+		return err
+	}
+
+	changeMeta, err := o.ChangeMetaFlags.AsMap()
+	if err != nil {
+		return err
+	}
+
+	err = app.CreateOrUpdate(appLabels)
+	if err != nil {
+		return err
+	}
+
+	err = app.Lock(ctlapp.CurrentOrigin().String(), o.LockTTL)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = app.Unlock() }()
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	resources, conf, err := o.candidateResources(supportObjs)
+	if err != nil {
+		return err
+	}
+
+	resources, err = o.unownedResources(resources, labelSelector)
+	if err != nil {
+		return err
+	}
+
+	printResourcesTable(o.ui, "Resources to adopt", resources)
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	var adoptedResources []ctlres.Resource
+
+	for _, res := range resources {
+		adoptedResources = append(adoptedResources, res.DeepCopy())
+	}
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	// Label scoping is not applied since it modifies resource spec (e.g. Service selectors)
+	noLabelScopingMods := func(map[string]string) []ctlres.StringMapAppendMod { return nil }
+
+	err = labeledResources.Prepare(adoptedResources, o.metadataOnlyMods(conf.OwnershipLabelMods()),
+		noLabelScopingMods, conf.AdditionalLabels())
+	if err != nil {
+		return err
+	}
+
+	touch := ctlapp.Touch{
+		App:         app,
+		Description: fmt.Sprintf("adopt: %d resource(s)", len(adoptedResources)),
+		Namespaces:  resourceNsNames(adoptedResources),
+		CustomMeta:  changeMeta,
+	}
+
+	return touch.Do(func() error {
+		for _, res := range adoptedResources {
+			_, err := supportObjs.IdentifiedResources.Update(res)
+			if err != nil {
+				return fmt.Errorf("Adopting resource '%s': %s", res.Description(), err)
+			}
+		}
+		return nil
+	})
+}
+
+func (o *AdoptOptions) candidateResources(supportObjs AppFactorySupportObjs) ([]ctlres.Resource, ctlconf.Conf, error) {
+	if len(o.Selector) > 0 {
+		_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		selector, err := labels.Parse(o.Selector)
+		if err != nil {
+			return nil, ctlconf.Conf{}, fmt.Errorf("Parsing selector: %s", err)
+		}
+
+		resources, err := supportObjs.IdentifiedResources.List(selector)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		var result []ctlres.Resource
+
+		for _, res := range resources {
+			if !o.AllNamespaces && res.Namespace() != o.AppFlags.NamespaceFlags.Name {
+				continue
+			}
+			// Resources created by controllers (e.g. Pods) are
+			// associated with an app through their owners
+			if len(res.OwnerRefs()) > 0 || controllerManagedFilter.Matches(res) {
+				continue
+			}
+			result = append(result, res)
+		}
+
+		return result, conf, nil
+	}
+
+	fileResources, err := resourcesFromFiles(o.FileFlags.Files)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	fileResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(fileResources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	prepOpts := ctlapp.PrepareResourcesOpts{DefaultNamespace: o.AppFlags.NamespaceFlags.Name}
+
+	fileResources, err = ctlapp.NewPreparation(supportObjs.ResourceTypes, prepOpts).PrepareResources(fileResources)
+	if err != nil {
+		return nil, ctlconf.Conf{}, err
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range fileResources {
+		exists, err := supportObjs.IdentifiedResources.Exists(res)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		if !exists {
+			o.ui.PrintLinef("Skipping resource '%s' since it does not exist", res.Description())
+			continue
+		}
+
+		clusterRes, err := supportObjs.IdentifiedResources.Get(res)
+		if err != nil {
+			return nil, ctlconf.Conf{}, err
+		}
+
+		result = append(result, clusterRes)
+	}
+
+	return result, conf, nil
+}
+
+func (o *AdoptOptions) unownedResources(resources []ctlres.Resource, labelSelector labels.Selector) ([]ctlres.Resource, error) {
+	labelKey, _, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range resources {
+		// Never adopt kapp state records
+		if _, found := res.Labels()[ctlapp.KappIsAppLabelKey]; found {
+			continue
+		}
+		if val, found := res.Labels()[labelKey]; found {
+			o.ui.PrintLinef("Skipping resource '%s' since it's already owned by an app (label '%s=%s')",
+				res.Description(), labelKey, val)
+			continue
+		}
+		result = append(result, res)
+	}
+
+	return result, nil
+}
+
+// metadataOnlyMods only keeps ownership label mods that apply to resource's
+// own labels, since labeling pod templates would trigger new rollouts
+func (o *AdoptOptions) metadataOnlyMods(olmFunc ctlres.OwnershipLabelModsFunc) ctlres.OwnershipLabelModsFunc {
+	metadataLabelsPath := ctlres.NewPathFromStrings([]string{"metadata", "labels"}).AsString()
+
+	return func(kvs map[string]string) []ctlres.StringMapAppendMod {
+		var result []ctlres.StringMapAppendMod
+		for _, mod := range olmFunc(kvs) {
+			if mod.Path.AsString() == metadataLabelsPath {
+				result = append(result, mod)
+			}
+		}
+		return result
+	}
+}
+
+func printResourcesTable(ui ui.UI, title string, resources []ctlres.Resource) {
+	table := uitable.Table{
+		Title:   title,
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+		},
+	}
+
+	for _, res := range resources {
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(res.Namespace()),
+			uitable.NewValueString(res.Name()),
+			uitable.NewValueString(res.Kind()),
+		})
+	}
+
+	ui.PrintTable(table)
+}
@@ -7,11 +7,13 @@ import (
 
 type AppFlags struct {
 	NamespaceFlags cmdcore.NamespaceFlags
+	AppStateFlags  AppStateFlags
 	Name           string
 }
 
 func (s *AppFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
 	s.NamespaceFlags.Set(cmd, flagsFactory)
+	s.AppStateFlags.Set(cmd)
 
 	cmd.Flags().StringVarP(&s.Name, "app", "a", "", "Set app name (or label selector) (format: name, label:key=val, !key)")
 }
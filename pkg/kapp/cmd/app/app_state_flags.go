@@ -0,0 +1,22 @@
+package app
+
+import (
+	"os"
+
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	"github.com/spf13/cobra"
+)
+
+type AppStateFlags struct {
+	Kind string
+}
+
+func (s *AppStateFlags) Set(cmd *cobra.Command) {
+	kind := os.Getenv("KAPP_APP_STATE_KIND")
+	if len(kind) == 0 {
+		kind = ctlapp.StateKindConfigMap
+	}
+
+	cmd.Flags().StringVar(&s.Kind, "app-state-kind", kind,
+		"Set kind of resource used to store app state (configmap, secret) ($KAPP_APP_STATE_KIND)")
+}
@@ -14,6 +14,9 @@ var (
 			ApplyIgnored: false,
 			Wait:         true,
 			WaitIgnored:  false,
+			DeleteChangeOpts: ctlcap.DeleteChangeOpts{
+				DefaultDeletePropagation: "background",
+			},
 		},
 	}
 	ApplyFlagsDeleteDefaults = ApplyFlags{
@@ -21,6 +24,9 @@ var (
 			ApplyIgnored: false,
 			Wait:         true,
 			WaitIgnored:  true,
+			DeleteChangeOpts: ctlcap.DeleteChangeOpts{
+				DefaultDeletePropagation: "background",
+			},
 		},
 	}
 )
@@ -40,6 +46,20 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 
 	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.DefaultUpdateStrategy, prefix+"apply-default-update-strategy",
 		defaults.AddOrUpdateChangeOpts.DefaultUpdateStrategy, "Change default update strategy")
+	cmd.Flags().StringVar(&s.AddOrUpdateChangeOpts.DefaultCreateStrategy, prefix+"apply-default-create-strategy",
+		defaults.AddOrUpdateChangeOpts.DefaultCreateStrategy, "Change default create strategy")
+	cmd.Flags().StringVar(&s.DeleteChangeOpts.DefaultDeletePropagation, prefix+"apply-default-delete-propagation",
+		defaults.DeleteChangeOpts.DefaultDeletePropagation, "Change default delete propagation (background, foreground, orphan)")
+
+	cmd.Flags().BoolVar(&s.ContinueOnError, prefix+"apply-continue-on-error", false, "Set to keep applying changes that do not depend on failed changes")
+	cmd.Flags().IntVar(&s.ApplyRetryOpts.MaxAttempts, prefix+"apply-retry-max-attempts", 8,
+		"Maximum number of attempts of API requests made to apply each change when they fail with transient errors (1 disables retries)")
+	cmd.Flags().DurationVar(&s.ApplyRetryOpts.InitialInterval, prefix+"apply-retry-initial-interval",
+		mustParseDuration("1s"), "Amount of time to wait before first retry (doubled after each retry)")
+	cmd.Flags().DurationVar(&s.ApplyRetryOpts.MaxInterval, prefix+"apply-retry-max-interval",
+		mustParseDuration("30s"), "Maximum amount of time to wait between retries")
+
+	cmd.Flags().BoolVar(&s.DryRunServer, prefix+"dry-run-server", false, "Set to check all changes via server-side dry run before applying any of them")
 
 	cmd.Flags().BoolVar(&s.Wait, prefix+"wait", defaults.Wait, "Set to wait for changes to be applied")
 	cmd.Flags().BoolVar(&s.WaitIgnored, prefix+"wait-ignored", defaults.WaitIgnored, "Set to wait for ignored changes to be applied")
@@ -48,6 +68,9 @@ func (s *ApplyFlags) SetWithDefaults(prefix string, defaults ApplyFlags, cmd *co
 		mustParseDuration("15m"), "Maximum amount of time to wait")
 	cmd.Flags().DurationVar(&s.WaitingChangesOpts.CheckInterval, prefix+"wait-check-interval",
 		mustParseDuration("1s"), "Amount of time to sleep between checks while waiting")
+
+	cmd.Flags().DurationVar(&s.DeleteChangeOpts.RemoveFinalizersAfter, prefix+"dangerous-remove-finalizers-after",
+		0, "Remove finalizers from resources that are still deleting after given amount of time (0 disables)")
 }
 
 func mustParseDuration(str string) time.Duration {
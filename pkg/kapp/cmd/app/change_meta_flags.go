@@ -0,0 +1,35 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type ChangeMetaFlags struct {
+	Meta []string
+}
+
+func (s *ChangeMetaFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&s.Meta, "change-meta", nil, "Set app change metadata (format: key=val) (can repeat)")
+}
+
+func (s *ChangeMetaFlags) AsMap() (map[string]string, error) {
+	if len(s.Meta) == 0 {
+		return nil, nil
+	}
+
+	result := map[string]string{}
+	for _, val := range s.Meta {
+		pieces := strings.SplitN(val, "=", 2)
+		if len(pieces) != 2 {
+			return nil, fmt.Errorf("Expected app change metadata to be in 'key=val' format")
+		}
+		if len(pieces[0]) == 0 {
+			return nil, fmt.Errorf("Expected app change metadata key to be non-empty")
+		}
+		result[pieces[0]] = pieces[1]
+	}
+	return result, nil
+}
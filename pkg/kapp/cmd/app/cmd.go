@@ -0,0 +1,17 @@
+package app
+
+import (
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "app",
+		Short: "App maintenance",
+		Annotations: map[string]string{
+			cmdcore.AppSupportHelpGroup.Key: cmdcore.AppSupportHelpGroup.Value,
+		},
+	}
+	return cmd
+}
@@ -1,16 +1,21 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/cppforlife/go-cli-ui/ui"
 	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
 	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
 	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
 	ctldgraph "github.com/k14s/kapp/pkg/kapp/diffgraph"
+	ctlhooks "github.com/k14s/kapp/pkg/kapp/hooks"
 	"github.com/k14s/kapp/pkg/kapp/logger"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 type DeleteOptions struct {
@@ -23,6 +28,7 @@ type DeleteOptions struct {
 	ResourceFilterFlags cmdtools.ResourceFilterFlags
 	ApplyFlags          ApplyFlags
 	ResourceTypesFlags  ResourceTypesFlags
+	OrphanAll           bool
 }
 
 func NewDeleteOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeleteOptions {
@@ -37,12 +43,19 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 		Annotations: map[string]string{
 			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
 		},
+		Example: `
+  # Delete app 'app1' and all of its resources
+  kapp delete -a app1
+
+  # Delete app 'app1' record, but keep its resources in the cluster
+  kapp delete -a app1 --orphan-all`,
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	o.DiffFlags.SetWithPrefix("diff", cmd)
 	o.ResourceFilterFlags.Set(cmd)
 	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeleteDefaults, cmd)
 	o.ResourceTypesFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.OrphanAll, "orphan-all", false, "Keep all resources, only removing kapp ownership labels and annotations from them")
 	return cmd
 }
 
@@ -68,13 +81,24 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	if o.OrphanAll {
+		return o.release(app, existingResources, fullyDeleteApp, supportObjs)
+	}
+
 	clusterChangeSet, clusterChangesGraph, err := o.calculateAndPresentChanges(existingResources, supportObjs)
 	if err != nil {
 		return err
 	}
 
+	hooks, conf, err := o.recordedHooks(app)
+	if err != nil {
+		return err
+	}
+
+	presentHooks(o.ui, hooks, ctlhooks.PhasePreDelete, ctlhooks.PhasePostDelete)
+
 	if o.DiffFlags.Run {
-		return nil
+		return o.DiffFlags.ExitStatusErr(len(existingResources) > 0)
 	}
 
 	err = o.ui.AskForConfirmation()
@@ -82,10 +106,35 @@ func (o *DeleteOptions) Run() error {
 		return err
 	}
 
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	hooksRunner := newHooksRunner(hooks, conf, o.DiffFlags.ChangeSetOpts,
+		o.ApplyFlags, labelSelector, supportObjs, o.ui, o.logger)
+
 	touch := ctlapp.Touch{App: app, Description: "delete", IgnoreSuccessErr: true}
 
 	return touch.Do(func() error {
-		err := clusterChangeSet.Apply(clusterChangesGraph)
+		err := hooksRunner.Run(ctlhooks.PhasePreDelete)
+		if err != nil {
+			return err
+		}
+
+		err = clusterChangeSet.Apply(clusterChangesGraph)
+		if err != nil {
+			return err
+		}
+
+		err = hooksRunner.Run(ctlhooks.PhasePostDelete)
+		if err != nil {
+			return err
+		}
+
+		// Delete hooks are labeled as app resources, hence they have to be
+		// deleted (regardless of their delete policies) together with the app
+		err = hooksRunner.DeleteInstances(ctlhooks.PhasePreDelete, ctlhooks.PhasePostDelete)
 		if err != nil {
 			return err
 		}
@@ -126,6 +175,13 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 			app.Name(), o.AppFlags.NamespaceFlags.Name)
 	}
 
+	if fullyDeleteApp && o.DiffFlags.ApplyFilter() != nil {
+		fullyDeleteApp = false
+		o.ui.PrintLinef("App '%s' (namespace: %s) will not be fully deleted "+
+			"because some changes may be excluded by diff filter",
+			app.Name(), o.AppFlags.NamespaceFlags.Name)
+	}
+
 	existingResources = applicableExistingResources
 
 	o.changeIgnored(existingResources)
@@ -133,13 +189,49 @@ func (o *DeleteOptions) existingResources(app ctlapp.App,
 	return existingResources, fullyDeleteApp, nil
 }
 
+// recordedHooks returns hooks (and configuration) recorded as part of
+// last successful deploy. Apps deployed before hooks were recorded have none.
+func (o *DeleteOptions) recordedHooks(app ctlapp.App) (ctlhooks.Hooks, ctlconf.Conf, error) {
+	_, defaultConf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		return ctlhooks.Hooks{}, ctlconf.Conf{}, err
+	}
+
+	change, err := successfulAppChange(app, 0)
+	if err != nil {
+		return ctlhooks.Hooks{}, defaultConf, nil
+	}
+
+	recordedResources, err := change.Resources()
+	if err != nil {
+		return ctlhooks.Hooks{}, defaultConf, nil
+	}
+
+	recordedResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(recordedResources)
+	if err != nil {
+		return ctlhooks.Hooks{}, ctlconf.Conf{}, err
+	}
+
+	hooks, _, err := ctlhooks.NewHooks(recordedResources)
+	if err != nil {
+		return hooks, ctlconf.Conf{}, err
+	}
+
+	return hooks, conf, nil
+}
+
 func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Resource,
 	supportObjs AppFactorySupportObjs) (ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, error) {
 
 	var clusterChangeSet ctlcap.ClusterChangeSet
 
 	{ // Figure out changes for X existing resources -> 0 new resources
-		changeFactory := ctldiff.NewChangeFactory(nil, nil)
+		_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+		if err != nil {
+			return ctlcap.ClusterChangeSet{}, nil, err
+		}
+
+		changeFactory := ctldiff.NewChangeFactory(nil, nil, o.DiffFlags.ChangeOpts(conf))
 		changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
 
 		changes, err := changeSetFactory.New(existingResources, nil).Calculate()
@@ -154,8 +246,11 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 				o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
 				changeFactory, changeSetFactory, msgsUI)
 
+			clusterChangeSetOpts := o.ApplyFlags.ClusterChangeSetOpts
+			clusterChangeSetOpts.Filter = o.DiffFlags.ApplyFilter()
+
 			clusterChangeSet = ctlcap.NewClusterChangeSet(
-				changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory, msgsUI)
+				changes, clusterChangeSetOpts, clusterChangeFactory, msgsUI)
 		}
 	}
 
@@ -165,9 +260,12 @@ func (o *DeleteOptions) calculateAndPresentChanges(existingResources []ctlres.Re
 	}
 
 	{ // Present cluster changes in UI
-		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
+		changeViews := o.DiffFlags.FilterChangeViews(ctlcap.ClusterChangesAsChangeViews(clusterChanges))
 		changeSetView := ctlcap.NewChangeSetView(changeViews, o.DiffFlags.ChangeSetViewOpts)
-		changeSetView.Print(o.ui)
+		err := changeSetView.Print(o.ui)
+		if err != nil {
+			return ctlcap.ClusterChangeSet{}, nil, err
+		}
 	}
 
 	return clusterChangeSet, clusterChangesGraph, nil
@@ -187,3 +285,70 @@ func (o *DeleteOptions) changeIgnored(resources []ctlres.Resource) {
 		}
 	}
 }
+
+// release removes kapp ownership from resources instead of deleting them.
+// Ownership labels are also removed from pod templates (based on ownership
+// label rules of last deploy's configuration), however changing selectors is
+// out of scope (they are immutable for most controllers), hence app label stays
+// in pod templates of resources with scoped selectors. Resources created by
+// controllers (transient) are left as is since they are managed by their owners.
+func (o *DeleteOptions) release(app ctlapp.App, existingResources []ctlres.Resource,
+	fullyDeleteApp bool, supportObjs AppFactorySupportObjs) error {
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	appLabelKey, _, err := ctlres.NewSimpleLabel(labelSelector).KV()
+	if err != nil {
+		return err
+	}
+
+	_, conf, err := o.recordedHooks(app)
+	if err != nil {
+		return err
+	}
+
+	var releasedResources []ctlres.Resource
+
+	for _, res := range existingResources {
+		if !res.Transient() {
+			releasedResources = append(releasedResources, res)
+		}
+	}
+
+	printResourcesTable(o.ui, "Resources to release", releasedResources)
+
+	if o.DiffFlags.Run {
+		return o.DiffFlags.ExitStatusErr(len(releasedResources) > 0)
+	}
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	touch := ctlapp.Touch{App: app, Description: "release", IgnoreSuccessErr: true}
+
+	return touch.Do(func() error {
+		for _, res := range releasedResources {
+			patchJSON, err := ctlres.NewReleasePatch(res, appLabelKey,
+				conf.OwnershipLabelMods(), conf.LabelScopingMods()).AsBytes()
+			if err != nil {
+				return err
+			}
+
+			_, err = supportObjs.IdentifiedResources.Patch(res, types.MergePatchType, patchJSON)
+			if err != nil {
+				return fmt.Errorf("Releasing resource '%s': %s", res.Description(), err)
+			}
+		}
+
+		if fullyDeleteApp {
+			return app.Release()
+		}
+
+		return nil
+	})
+}
@@ -12,6 +12,7 @@ import (
 	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
 	ctldgraph "github.com/k14s/kapp/pkg/kapp/diffgraph"
+	ctlhooks "github.com/k14s/kapp/pkg/kapp/hooks"
 	"github.com/k14s/kapp/pkg/kapp/logger"
 	ctllogs "github.com/k14s/kapp/pkg/kapp/logs"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
@@ -34,6 +35,7 @@ type DeployOptions struct {
 	DeployFlags         DeployFlags
 	ResourceTypesFlags  ResourceTypesFlags
 	LabelFlags          LabelFlags
+	ChangeMetaFlags     ChangeMetaFlags
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeployOptions {
@@ -72,6 +74,7 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.DeployFlags.Set(cmd)
 	o.ResourceTypesFlags.Set(cmd)
 	o.LabelFlags.Set(cmd)
+	o.ChangeMetaFlags.Set(cmd)
 
 	return cmd
 }
@@ -82,16 +85,63 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
+	newResources, err := o.newResourcesFromFiles()
+	if err != nil {
+		return err
+	}
+
+	return o.deploy(app, supportObjs, deploySource{Resources: newResources, Description: "update"})
+}
+
+// deploySource describes resources that are being deployed
+type deploySource struct {
+	Resources []ctlres.Resource
+	// Prepared indicates that resources were already prepared
+	// (e.g. placed into namespaces) as part of a previous deploy
+	Prepared    bool
+	Description string
+	// Confirmed indicates that changes do not need to be confirmed
+	// (e.g. when rolling back after a failed deploy)
+	Confirmed bool
+	// Locked indicates that app lock is already held by the caller
+	Locked bool
+	// SkipHooks indicates that hooks found in resources should not be run
+	SkipHooks bool
+}
+
+func (o *DeployOptions) deploy(app ctlapp.App, supportObjs AppFactorySupportObjs, source deploySource) error {
+	if o.DiffFlags.ExitStatus && !o.DiffFlags.Run {
+		return fmt.Errorf("Expected --diff-run to be specified when --diff-exit-status is used")
+	}
+
 	appLabels, err := o.LabelFlags.AsMap()
 	if err != nil {
 		return err
 	}
 
+	changeMeta, err := o.ChangeMetaFlags.AsMap()
+	if err != nil {
+		return err
+	}
+
 	err = app.CreateOrUpdate(appLabels)
 	if err != nil {
 		return err
 	}
 
+	// Diff run does not apply changes, hence it should not
+	// require write access to app or wait for other deploys
+	if !o.DiffFlags.Run && !source.Locked {
+		err = app.Lock(ctlapp.CurrentOrigin().String(), o.DeployFlags.LockTTL)
+		if err != nil {
+			return err
+		}
+
+		// Lock is released once changes are applied; however
+		// make sure it's not left behind if deploy ends earlier
+		defer func() { _ = app.Unlock() }()
+	}
+
 	o.DeployFlags.PrepareResourcesOpts.DefaultNamespace = o.AppFlags.NamespaceFlags.Name
 
 	prep := ctlapp.NewPreparation(supportObjs.ResourceTypes, o.DeployFlags.PrepareResourcesOpts)
@@ -108,37 +158,61 @@ func (o *DeployOptions) Run() error {
 		return err
 	}
 
-	newResources, conf, nsNames, err := o.newResources(prep, labeledResources, resourceFilter)
+	newResources, conf, nsNames, err := o.newResources(source, prep, labeledResources, resourceFilter)
 	if err != nil {
 		return err
 	}
 
-	existingResources, err := o.existingResources(newResources, labeledResources, resourceFilter)
+	// Record resources before versioned resources get their names assigned
+	// during change calculation, so that they could be deployed again later
+	recordedResources, recordedMasked, err := o.recordedResources(conf, newResources)
 	if err != nil {
 		return err
 	}
 
-	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
-		o.calculateAndPresentChanges(existingResources, newResources, conf, supportObjs)
+	hooks, newResources, err := ctlhooks.NewHooks(newResources)
 	if err != nil {
 		return err
 	}
 
-	// Validate new resources _after_ presenting changes to make it easier to see big picture
-	err = prep.ValidateResources(newResources)
+	existingResources, err := o.existingResources(newResources, hooks, labeledResources, resourceFilter)
 	if err != nil {
 		return err
 	}
 
-	if o.DiffFlags.Run || hasNoChanges {
-		return nil
+	if source.SkipHooks {
+		hooks = ctlhooks.Hooks{}
 	}
 
-	err = o.ui.AskForConfirmation()
+	clusterChangeSet, clusterChangesGraph, hasNoChanges, changeSummary, err :=
+		o.calculateAndPresentChanges(existingResources, newResources, conf, labelSelector, supportObjs)
 	if err != nil {
 		return err
 	}
 
+	presentHooks(o.ui, hooks, ctlhooks.PhasePreDeploy, ctlhooks.PhasePostDeploy)
+
+	// Validate new resources _after_ presenting changes to make it easier to see big picture
+	err = prep.ValidateResources(append(newResources, hooks.Resources()...))
+	if err != nil {
+		return err
+	}
+
+	// Hooks run on each deploy even if there are no changes to app resources
+	hasNoHooks := len(hooks.ForPhase(ctlhooks.PhasePreDeploy)) == 0 &&
+		len(hooks.ForPhase(ctlhooks.PhasePostDeploy)) == 0
+
+	if o.DiffFlags.Run || (hasNoChanges && hasNoHooks) {
+		return o.DiffFlags.ExitStatusErr(!hasNoChanges)
+	}
+
+	if !source.Confirmed {
+		err = o.ui.AskForConfirmation()
+		if err != nil {
+			return err
+		}
+	}
+
 	if o.DeployFlags.Logs {
 		cancelLogsCh := make(chan struct{})
 		defer func() { close(cancelLogsCh) }()
@@ -154,35 +228,157 @@ func (o *DeployOptions) Run() error {
 
 	touch := ctlapp.Touch{
 		App:              app,
-		Description:      "update: " + changeSummary,
+		Description:      source.Description + ": " + changeSummary,
 		Namespaces:       nsNames,
 		IgnoreSuccessErr: true,
+		Resources:        recordedResources,
+		Partial:          o.partial() || recordedMasked,
+		CustomMeta:       changeMeta,
+		// Keep app locked until rollback is finished
+		KeepLockOnFailure: o.DeployFlags.RollbackOnFailure,
 	}
 
-	return touch.Do(func() error {
-		return clusterChangeSet.Apply(clusterChangesGraph)
+	hooksRunner := newHooksRunner(hooks, conf, o.DiffFlags.ChangeSetOpts,
+		o.ApplyFlags, labelSelector, supportObjs, o.ui, o.logger)
+
+	err = touch.Do(func() error {
+		err := hooksRunner.Run(ctlhooks.PhasePreDeploy)
+		if err != nil {
+			return err
+		}
+
+		err = clusterChangeSet.Apply(clusterChangesGraph)
+		if err != nil {
+			return err
+		}
+
+		return hooksRunner.Run(ctlhooks.PhasePostDeploy)
 	})
+	if err != nil && o.DeployFlags.RollbackOnFailure {
+		return o.rollbackAfterFailure(app, supportObjs, err)
+	}
+
+	return err
 }
 
-func (o *DeployOptions) newResources(
-	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
-	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, error) {
+// rollbackAfterFailure deploys resources recorded by the last successful
+// app change (failed app change is recorded as unsuccessful, hence skipped).
+// App lock held by the failed deploy is used for the rollback, so that
+// other deploys could not start in between. Hooks are not run during rollback.
+func (o *DeployOptions) rollbackAfterFailure(app ctlapp.App,
+	supportObjs AppFactorySupportObjs, deployErr error) error {
 
-	newResources, err := o.newResourcesFromFiles()
+	change, err := successfulAppChange(app, 0)
 	if err != nil {
-		return nil, ctlconf.Conf{}, nil, err
+		return fmt.Errorf("%s\n\nRollback was not attempted: %s", deployErr, err)
 	}
 
-	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(newResources)
+	resources, err := change.Resources()
 	if err != nil {
-		return nil, ctlconf.Conf{}, nil, err
+		return fmt.Errorf("%s\n\nRollback was not attempted: %s", deployErr, err)
+	}
+
+	o.ui.PrintLinef("Deploy failed, rolling back app '%s' (namespace: %s) to app change '%s' (started at %s)",
+		app.Name(), o.AppFlags.NamespaceFlags.Name, change.Name(), change.Meta().StartedAt)
+
+	rollbackOpts := *o
+	rollbackOpts.DeployFlags.RollbackOnFailure = false
+	// Logs are still shown by the failed deploy
+	rollbackOpts.DeployFlags.Logs = false
+	// Entire recorded app change is restored even if failed
+	// deploy was limited to a subset of resources or changes
+	rollbackOpts.DeployFlags.Patch = false
+	rollbackOpts.ResourceFilterFlags = cmdtools.ResourceFilterFlags{}
+	rollbackOpts.DiffFlags.Filter = nil
+
+	source := deploySource{
+		Resources:   resources,
+		Prepared:    true,
+		Description: "rollback to " + change.Name() + " after failed deploy",
+		Confirmed:   true,
+		Locked:      true,
+		SkipHooks:   true,
+	}
+
+	err = rollbackOpts.deploy(app, supportObjs, source)
+	if err != nil {
+		return fmt.Errorf("%s\n\nRollback to app change '%s' failed: %s", deployErr, change.Name(), err)
 	}
 
-	newResources, err = prep.PrepareResources(newResources)
+	return fmt.Errorf("%s\n\nRolled back to app change '%s'", deployErr, change.Name())
+}
+
+// recordedResources returns resources to be recorded as part of app change.
+// Sensitive values (e.g. Secret data) are masked unless app state is stored
+// in Secrets; such app change cannot be used to rollback, hence returned bool.
+func (o *DeployOptions) recordedResources(conf ctlconf.Conf,
+	newResources []ctlres.Resource) ([]ctlres.Resource, bool, error) {
+
+	var result []ctlres.Resource
+	var masked bool
+
+	for _, res := range append(conf.Resources(), newResources...) {
+		res = res.DeepCopy()
+
+		if o.AppFlags.AppStateFlags.Kind != ctlapp.StateKindSecret {
+			maskedRes := res.DeepCopy()
+
+			for _, t := range conf.DiffMaskMods() {
+				err := t.Apply(maskedRes)
+				if err != nil {
+					return nil, false, err
+				}
+			}
+
+			if !maskedRes.Equal(res) {
+				masked = true
+				res = maskedRes
+			}
+		}
+
+		result = append(result, res)
+	}
+
+	if masked && !o.DiffFlags.Run {
+		o.ui.PrintLinef("Sensitive values (e.g. Secret data) are masked in recorded resources since app state " +
+			"is not stored in Secrets, hence this app change cannot be rolled back to (see --app-state-kind)")
+	}
+
+	return result, masked, nil
+}
+
+// partial indicates that deploy only affects subset of app resources
+// hence its recorded resources should not be used to rollback entire app
+func (o *DeployOptions) partial() bool {
+	return o.DeployFlags.Patch || !o.ResourceFilterFlags.Empty() || o.DiffFlags.ApplyFilter() != nil
+}
+
+func (o *DeployOptions) newResources(source deploySource,
+	prep ctlapp.Preparation, labeledResources *ctlres.LabeledResources,
+	resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, ctlconf.Conf, []string, error) {
+
+	newResources, conf, err := ctlconf.NewConfFromResourcesWithDefaults(source.Resources)
 	if err != nil {
 		return nil, ctlconf.Conf{}, nil, err
 	}
 
+	if source.Prepared {
+		// Recorded resources with masked values (e.g. Secret data recorded
+		// while app state was not stored in Secrets) would overwrite actual values
+		for _, res := range newResources {
+			if ctlres.HasMaskedValues(res) {
+				return nil, ctlconf.Conf{}, nil, fmt.Errorf("Expected recorded resource %s to not include "+
+					"masked values (app change was recorded without --app-state-kind=secret), "+
+					"hence it cannot be deployed again", res.Description())
+			}
+		}
+	} else {
+		newResources, err = prep.PrepareResources(newResources)
+		if err != nil {
+			return nil, ctlconf.Conf{}, nil, err
+		}
+	}
+
 	err = labeledResources.Prepare(newResources, conf.OwnershipLabelMods(),
 		conf.LabelScopingMods(), conf.AdditionalLabels())
 	if err != nil {
@@ -190,15 +386,19 @@ func (o *DeployOptions) newResources(
 	}
 
 	// Grab ns names before resource filtering is applied
-	nsNames := o.nsNames(newResources)
+	nsNames := resourceNsNames(newResources)
 
 	return resourceFilter.Apply(newResources), conf, nsNames, nil
 }
 
 func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
+	return resourcesFromFiles(o.FileFlags.Files)
+}
+
+func resourcesFromFiles(files []string) ([]ctlres.Resource, error) {
 	var allResources []ctlres.Resource
 
-	for _, file := range o.FileFlags.Files {
+	for _, file := range files {
 		fileRs, err := ctlres.NewFileResources(file)
 		if err != nil {
 			return nil, err
@@ -217,7 +417,7 @@ func (o *DeployOptions) newResourcesFromFiles() ([]ctlres.Resource, error) {
 	return allResources, nil
 }
 
-func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
+func (o *DeployOptions) existingResources(newResources []ctlres.Resource, hooks ctlhooks.Hooks,
 	labeledResources *ctlres.LabeledResources, resourceFilter ctlres.ResourceFilter) ([]ctlres.Resource, error) {
 
 	matchingOpts := ctlres.AllAndMatchingOpts{
@@ -243,19 +443,35 @@ func (o *DeployOptions) existingResources(newResources []ctlres.Resource,
 		}
 	}
 
-	return resourceFilter.Apply(existingResources), nil
+	existingResources = resourceFilter.Apply(existingResources)
+
+	// Previously created hooks are not diffed against app resources,
+	// except for ones that are no longer configured, hence get deleted
+	staleHookInstances := hooks.StaleInstances(existingResources)
+
+	return append(ctlhooks.WithoutInstances(existingResources), staleHookInstances...), nil
 }
 
 func (o *DeployOptions) calculateAndPresentChanges(existingResources,
-	newResources []ctlres.Resource, conf ctlconf.Conf, supportObjs AppFactorySupportObjs) (
-	ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, bool, string, error) {
+	newResources []ctlres.Resource, conf ctlconf.Conf, labelSelector labels.Selector,
+	supportObjs AppFactorySupportObjs) (ctlcap.ClusterChangeSet, *ctldgraph.ChangeGraph, bool, string, error) {
 
 	var clusterChangeSet ctlcap.ClusterChangeSet
 
 	{ // Figure out changes for X existing resources -> X new resources
-		changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(), conf.DiffAgainstLastAppliedFieldExclusionMods())
+		changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(),
+			conf.DiffAgainstLastAppliedFieldExclusionMods(), o.DiffFlags.ChangeOpts(conf))
 		changeSetFactory := ctldiff.NewChangeSetFactory(o.DiffFlags.ChangeSetOpts, changeFactory)
 
+		// Drift report is shown before changes so that changes made
+		// outside of kapp could be told apart from changes in new config
+		if o.DiffFlags.ExitStatus && o.DiffFlags.Format == ctlcap.ChangeSetViewFormatText {
+			err := o.presentDrift(existingResources, changeFactory)
+			if err != nil {
+				return clusterChangeSet, nil, false, "", err
+			}
+		}
+
 		changes, err := ctldiff.NewChangeSetWithTemplates(
 			existingResources, newResources, conf.TemplateRules(),
 			o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
@@ -265,12 +481,19 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 
 		msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(o.ui))
 
+		clusterChangeOpts := o.ApplyFlags.ClusterChangeOpts
+		clusterChangeOpts.AppLabelSelector = labelSelector
+		clusterChangeOpts.SkipResourceOwnershipCheck = o.DeployFlags.OverrideOwnershipOfExistingResources
+
 		clusterChangeFactory := ctlcap.NewClusterChangeFactory(
-			o.ApplyFlags.ClusterChangeOpts, supportObjs.IdentifiedResources,
+			clusterChangeOpts, supportObjs.IdentifiedResources,
 			changeFactory, changeSetFactory, msgsUI)
 
+		clusterChangeSetOpts := o.ApplyFlags.ClusterChangeSetOpts
+		clusterChangeSetOpts.Filter = o.DiffFlags.ApplyFilter()
+
 		clusterChangeSet = ctlcap.NewClusterChangeSet(
-			changes, o.ApplyFlags.ClusterChangeSetOpts, clusterChangeFactory, msgsUI)
+			changes, clusterChangeSetOpts, clusterChangeFactory, msgsUI)
 	}
 
 	clusterChanges, clusterChangesGraph, err := clusterChangeSet.Calculate()
@@ -281,15 +504,48 @@ func (o *DeployOptions) calculateAndPresentChanges(existingResources,
 	var changesSummary string
 
 	{ // Present cluster changes in UI
-		changeViews := ctlcap.ClusterChangesAsChangeViews(clusterChanges)
+		changeViews := o.DiffFlags.FilterChangeViews(ctlcap.ClusterChangesAsChangeViews(clusterChanges))
 		changeSetView := ctlcap.NewChangeSetView(changeViews, o.DiffFlags.ChangeSetViewOpts)
-		changeSetView.Print(o.ui)
-		changesSummary = changeSetView.Summary()
+		err := changeSetView.Print(o.ui)
+		if err != nil {
+			return clusterChangeSet, nil, false, "", err
+		}
+		// Summary is recorded in app change description, hence
+		// it should include all changes that are about to be applied
+		changesSummary = ctlcap.NewChangeSetView(ctlcap.ClusterChangesAsChangeViews(
+			clusterChanges), o.DiffFlags.ChangeSetViewOpts).Summary()
 	}
 
 	return clusterChangeSet, clusterChangesGraph, (len(clusterChanges) == 0), changesSummary, err
 }
 
+func (o *DeployOptions) presentDrift(existingResources []ctlres.Resource, changeFactory ctldiff.ChangeFactory) error {
+	var driftedResources []ctlres.Resource
+
+	for _, res := range existingResources {
+		change, err := changeFactory.NewChangeSinceLastApplied(res)
+		if err != nil {
+			return err
+		}
+
+		if change == nil {
+			continue
+		}
+
+		if o.DiffFlags.Changes {
+			textDiffView := ctldiff.NewTextDiffView(change.TextDiff(), o.DiffFlags.TextDiffViewOpts)
+			o.ui.BeginLinef("--- drift %s\n", res.Description())
+			o.ui.PrintBlock([]byte(textDiffView.String()))
+		}
+
+		driftedResources = append(driftedResources, res)
+	}
+
+	printResourcesTable(o.ui, "Resources changed outside of kapp since last deploy", driftedResources)
+
+	return nil
+}
+
 const (
 	deployLogsAnnKey = "kapp.k14s.io/deploy-logs" // valid value is ''
 )
@@ -311,7 +567,7 @@ func (o *DeployOptions) showLogs(
 	ctllogs.NewView(logOpts, podWatcher, coreClient, o.ui).Show(cancelCh)
 }
 
-func (o *DeployOptions) nsNames(resources []ctlres.Resource) []string {
+func resourceNsNames(resources []ctlres.Resource) []string {
 	uniqNames := map[string]struct{}{}
 	names := []string{}
 	for _, res := range resources {
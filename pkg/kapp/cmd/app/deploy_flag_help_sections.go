@@ -20,6 +20,7 @@ var (
 		ExactMatch: []string{
 			"dangerous-allow-empty-list-of-resources",
 			"dangerous-override-ownership-of-existing-resources",
+			"dangerous-remove-finalizers-after",
 		},
 	}
 	WaitFlagGroup = cobrautil.FlagHelpSection{
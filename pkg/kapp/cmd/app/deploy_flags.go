@@ -2,6 +2,8 @@ package app
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 
 	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
@@ -16,8 +18,12 @@ type DeployFlags struct {
 
 	AppChangesMaxToKeep int
 
+	LockTTL time.Duration
+
 	Logs    bool
 	LogsAll bool
+
+	RollbackOnFailure bool
 }
 
 func (s *DeployFlags) Set(cmd *cobra.Command) {
@@ -37,6 +43,11 @@ func (s *DeployFlags) Set(cmd *cobra.Command) {
 
 	cmd.Flags().IntVar(&s.AppChangesMaxToKeep, "app-changes-max-to-keep", ctlapp.AppChangesMaxToKeepDefault, "Maximum number of app changes to keep")
 
+	cmd.Flags().DurationVar(&s.LockTTL, "lock-ttl", 30*time.Minute, "Maximum amount of time app lock is held before it's considered stale")
+
 	cmd.Flags().BoolVar(&s.Logs, "logs", true, fmt.Sprintf("Show logs from Pods annotated as '%s'", deployLogsAnnKey))
 	cmd.Flags().BoolVar(&s.LogsAll, "logs-all", false, "Show logs from all Pods")
+
+	cmd.Flags().BoolVar(&s.RollbackOnFailure, "rollback-on-failure", false,
+		"Set to rollback app to last successful app change if applying changes fails")
 }
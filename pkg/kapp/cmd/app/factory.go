@@ -15,7 +15,7 @@ type AppFactorySupportObjs struct {
 	Apps                ctlapp.Apps
 }
 
-func AppFactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFlags,
+func AppFactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.NamespaceFlags, appStateFlags AppStateFlags,
 	resTypesFlags ResourceTypesFlags, logger logger.Logger) (AppFactorySupportObjs, error) {
 
 	coreClient, err := depsFactory.CoreClient()
@@ -23,6 +23,11 @@ func AppFactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.Namespac
 		return AppFactorySupportObjs{}, err
 	}
 
+	stateStorage, err := ctlapp.NewStateStorage(appStateFlags.Kind, coreClient)
+	if err != nil {
+		return AppFactorySupportObjs{}, err
+	}
+
 	dynamicClient, err := depsFactory.DynamicClient()
 	if err != nil {
 		return AppFactorySupportObjs{}, err
@@ -39,7 +44,7 @@ func AppFactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.Namespac
 		CoreClient:          coreClient,
 		ResourceTypes:       resTypes,
 		IdentifiedResources: identifiedResources,
-		Apps:                ctlapp.NewApps(nsFlags.Name, coreClient, identifiedResources, logger),
+		Apps:                ctlapp.NewApps(nsFlags.Name, stateStorage, identifiedResources, logger),
 	}
 
 	return result, nil
@@ -48,7 +53,7 @@ func AppFactoryClients(depsFactory cmdcore.DepsFactory, nsFlags cmdcore.Namespac
 func AppFactory(depsFactory cmdcore.DepsFactory, appFlags AppFlags,
 	resTypesFlags ResourceTypesFlags, logger logger.Logger) (ctlapp.App, AppFactorySupportObjs, error) {
 
-	supportingObjs, err := AppFactoryClients(depsFactory, appFlags.NamespaceFlags, resTypesFlags, logger)
+	supportingObjs, err := AppFactoryClients(depsFactory, appFlags.NamespaceFlags, appFlags.AppStateFlags, resTypesFlags, logger)
 	if err != nil {
 		return nil, AppFactorySupportObjs{}, err
 	}
@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlhooks "github.com/k14s/kapp/pkg/kapp/hooks"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func presentHooks(ui ui.UI, hooks ctlhooks.Hooks, phases ...ctlhooks.Phase) {
+	for _, phase := range phases {
+		hookRs := hooks.ForPhase(phase)
+		if len(hookRs) > 0 {
+			printResourcesTable(ui, fmt.Sprintf("Hooks (%s)", phase), hookRs)
+		}
+	}
+}
+
+type hooksRunner struct {
+	hooks                ctlhooks.Hooks
+	changeFactory        ctldiff.ChangeFactory
+	changeSetFactory     ctldiff.ChangeSetFactory
+	clusterChangeOpts    ctlcap.ClusterChangeOpts
+	clusterChangeSetOpts ctlcap.ClusterChangeSetOpts
+	labeledResources     *ctlres.LabeledResources
+	identifiedResources  ctlres.IdentifiedResources
+	ui                   ui.UI
+}
+
+func newHooksRunner(hooks ctlhooks.Hooks, conf ctlconf.Conf, changeSetOpts ctldiff.ChangeSetOpts,
+	applyFlags ApplyFlags, labelSelector labels.Selector, supportObjs AppFactorySupportObjs,
+	ui ui.UI, logger logger.Logger) hooksRunner {
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(),
+		conf.DiffAgainstLastAppliedFieldExclusionMods(), ctldiff.ChangeOpts{MaskMods: conf.DiffMaskMods()})
+
+	clusterChangeOpts := applyFlags.ClusterChangeOpts
+	clusterChangeOpts.AppLabelSelector = labelSelector
+
+	return hooksRunner{
+		hooks:                hooks,
+		changeFactory:        changeFactory,
+		changeSetFactory:     ctldiff.NewChangeSetFactory(changeSetOpts, changeFactory),
+		clusterChangeOpts:    clusterChangeOpts,
+		clusterChangeSetOpts: applyFlags.ClusterChangeSetOpts,
+		labeledResources:     ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, logger),
+		identifiedResources:  supportObjs.IdentifiedResources,
+		ui:                   ui,
+	}
+}
+
+// Run creates hooks of a given phase (with the same ordering rules as
+// other changes) and waits for them to finish (e.g. Jobs to complete)
+func (r hooksRunner) Run(phase ctlhooks.Phase) error {
+	hookRs := r.hooks.ForPhase(phase)
+	if len(hookRs) == 0 {
+		return nil
+	}
+
+	msgsUI := cmdcore.NewDedupingMessagesUI(cmdcore.NewPlainMessagesUI(r.ui))
+	msgsUI.NotifySection("running %d %s hooks", len(hookRs), phase)
+
+	for _, res := range hookRs {
+		if ctlhooks.HasDeletePolicy(res, ctlhooks.DeletePolicyBeforeCreation) {
+			err := r.deleteInstance(res)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	applyErr := r.apply(hookRs, msgsUI)
+
+	policy := ctlhooks.DeletePolicySucceeded
+	if applyErr != nil {
+		policy = ctlhooks.DeletePolicyFailed
+	}
+
+	for _, res := range hookRs {
+		if ctlhooks.HasDeletePolicy(res, policy) {
+			err := r.identifiedResources.Delete(res, ctlres.DeleteOpts{})
+			if err != nil && applyErr == nil {
+				return fmt.Errorf("Deleting %s hook: %s", phase, err)
+			}
+		}
+	}
+
+	if applyErr != nil {
+		return fmt.Errorf("Running %s hooks: %s", phase, applyErr)
+	}
+
+	return nil
+}
+
+func (r hooksRunner) apply(hookRs []ctlres.Resource, msgsUI ctlcap.UI) error {
+	changes, err := r.changeSetFactory.New(nil, hookRs).Calculate()
+	if err != nil {
+		return err
+	}
+
+	// Hooks have to be waited on before moving on to the next phase
+	clusterChangeOpts := r.clusterChangeOpts
+	clusterChangeOpts.Wait = true
+
+	clusterChangeFactory := ctlcap.NewClusterChangeFactory(
+		clusterChangeOpts, r.identifiedResources, r.changeFactory, r.changeSetFactory, msgsUI)
+
+	clusterChangeSetOpts := r.clusterChangeSetOpts
+	clusterChangeSetOpts.Filter = nil
+
+	clusterChangeSet := ctlcap.NewClusterChangeSet(
+		changes, clusterChangeSetOpts, clusterChangeFactory, msgsUI)
+
+	_, clusterChangesGraph, err := clusterChangeSet.Calculate()
+	if err != nil {
+		return err
+	}
+
+	return clusterChangeSet.Apply(clusterChangesGraph)
+}
+
+// DeleteInstances deletes hooks of given phases regardless of their
+// delete policies (e.g. delete hooks since app is being deleted)
+func (r hooksRunner) DeleteInstances(phases ...ctlhooks.Phase) error {
+	for _, phase := range phases {
+		for _, res := range r.hooks.ForPhase(phase) {
+			err := r.deleteInstance(res)
+			if err != nil {
+				return fmt.Errorf("Deleting %s hook: %s", phase, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteInstance deletes hook created by a previous deploy (or delete)
+// and waits for it to be fully deleted so that it could be created again
+func (r hooksRunner) deleteInstance(res ctlres.Resource) error {
+	existingRes, err := r.identifiedResources.Get(res)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	err = r.labeledResources.CheckResourceOwnership([]ctlres.Resource{existingRes})
+	if err != nil {
+		return err
+	}
+
+	err = r.identifiedResources.Delete(existingRes, ctlres.DeleteOpts{})
+	if err != nil {
+		return err
+	}
+
+	startTime := time.Now()
+
+	for {
+		exists, err := r.identifiedResources.Exists(existingRes)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return nil
+		}
+		if time.Now().Sub(startTime) > r.clusterChangeSetOpts.WaitingChangesOpts.Timeout {
+			return fmt.Errorf("Timed out waiting for hook %s to be deleted", existingRes.Description())
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
@@ -18,6 +18,7 @@ type ListOptions struct {
 	logger      logger.Logger
 
 	NamespaceFlags cmdcore.NamespaceFlags
+	AppStateFlags  AppStateFlags
 	AllNamespaces  bool
 }
 
@@ -36,6 +37,7 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		},
 	}
 	o.NamespaceFlags.Set(cmd, flagsFactory)
+	o.AppStateFlags.Set(cmd)
 	cmd.Flags().BoolVarP(&o.AllNamespaces, "all-namespaces", "A", false, "List apps in all namespaces")
 	return cmd
 }
@@ -51,7 +53,7 @@ func (o *ListOptions) Run() error {
 		nsHeader.Hidden = false
 	}
 
-	supportObjs, err := AppFactoryClients(o.depsFactory, o.NamespaceFlags, ResourceTypesFlags{}, o.logger)
+	supportObjs, err := AppFactoryClients(o.depsFactory, o.NamespaceFlags, o.AppStateFlags, ResourceTypesFlags{}, o.logger)
 	if err != nil {
 		return err
 	}
@@ -14,8 +14,9 @@ type RenameOptions struct {
 	depsFactory cmdcore.DepsFactory
 	logger      logger.Logger
 
-	AppFlags AppFlags
-	NewName  string
+	AppFlags     AppFlags
+	NewName      string
+	NewNamespace string
 }
 
 func NewRenameOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *RenameOptions {
@@ -33,6 +34,7 @@ func NewRenameCmd(o *RenameOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
 	cmd.Flags().StringVar(&o.NewName, "new-name", "", "Set new name (format: new-name)")
+	cmd.Flags().StringVar(&o.NewNamespace, "new-namespace", "", "Set new state namespace (defaults to current state namespace)")
 	return cmd
 }
 
@@ -51,13 +53,23 @@ func (o *RenameOptions) Run() error {
 		return fmt.Errorf("App '%s' (namespace: %s) does not exist", app.Name(), o.AppFlags.NamespaceFlags.Name)
 	}
 
-	o.ui.PrintLinef("Renaming '%s' (namespace: %s) to '%s' (app changes will not be renamed)",
-		app.Name(), o.AppFlags.NamespaceFlags.Name, o.NewName)
+	newName := o.NewName
+	if len(newName) == 0 {
+		newName = app.Name()
+	}
+
+	newNamespace := o.NewNamespace
+	if len(newNamespace) == 0 {
+		newNamespace = o.AppFlags.NamespaceFlags.Name
+	}
+
+	o.ui.PrintLinef("Renaming '%s' (namespace: %s) to '%s' (namespace: %s)",
+		app.Name(), o.AppFlags.NamespaceFlags.Name, newName, newNamespace)
 
 	err = o.ui.AskForConfirmation()
 	if err != nil {
 		return err
 	}
 
-	return app.Rename(o.NewName)
+	return app.Rename(newName, newNamespace)
 }
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	"github.com/spf13/cobra"
+)
+
+type RollbackOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            AppFlags
+	DiffFlags           cmdtools.DiffFlags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ApplyFlags          ApplyFlags
+	DeployFlags         DeployFlags
+	ResourceTypesFlags  ResourceTypesFlags
+	LabelFlags          LabelFlags
+	ChangeMetaFlags     ChangeMetaFlags
+
+	ToChange string
+	Steps    int
+}
+
+func NewRollbackOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *RollbackOptions {
+	return &RollbackOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewRollbackCmd(o *RollbackOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback",
+		Short: "Rollback app to resources recorded in a previous app change",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+		Example: `
+  # Rollback app 'app1' to previous successful app change
+  kapp rollback -a app1 --steps 1
+
+  # Rollback app 'app1' to particular app change (see 'kapp app-change list')
+  kapp rollback -a app1 --to-change app1-change-abc12`,
+	}
+
+	setDeployCmdFlags(cmd)
+
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.DiffFlags.SetWithPrefix("diff", cmd)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ApplyFlags.SetWithDefaults("", ApplyFlagsDeployDefaults, cmd)
+	o.DeployFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	o.LabelFlags.Set(cmd)
+	o.ChangeMetaFlags.Set(cmd)
+
+	cmd.Flags().StringVar(&o.ToChange, "to-change", "", "Set app change to rollback to")
+	cmd.Flags().IntVar(&o.Steps, "steps", 0, "Set number of successful app changes to go back (alternative to --to-change)")
+
+	return cmd
+}
+
+func (o *RollbackOptions) Run() error {
+	app, supportObjs, err := AppFactory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	exists, err := app.Exists()
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("App '%s' (namespace: %s) does not exist", app.Name(), o.AppFlags.NamespaceFlags.Name)
+	}
+
+	change, err := o.targetChange(app)
+	if err != nil {
+		return err
+	}
+
+	resources, err := change.Resources()
+	if err != nil {
+		return err
+	}
+
+	o.ui.PrintLinef("Rolling back app '%s' (namespace: %s) to app change '%s' (started at %s)",
+		app.Name(), o.AppFlags.NamespaceFlags.Name, change.Name(), change.Meta().StartedAt)
+
+	deployOpts := DeployOptions{
+		ui:          o.ui,
+		depsFactory: o.depsFactory,
+		logger:      o.logger,
+
+		AppFlags:            o.AppFlags,
+		DiffFlags:           o.DiffFlags,
+		ResourceFilterFlags: o.ResourceFilterFlags,
+		ApplyFlags:          o.ApplyFlags,
+		DeployFlags:         o.DeployFlags,
+		ResourceTypesFlags:  o.ResourceTypesFlags,
+		LabelFlags:          o.LabelFlags,
+		ChangeMetaFlags:     o.ChangeMetaFlags,
+	}
+
+	source := deploySource{
+		Resources:   resources,
+		Prepared:    true,
+		Description: "rollback to " + change.Name(),
+	}
+
+	return deployOpts.deploy(app, supportObjs, source)
+}
+
+func (o *RollbackOptions) targetChange(app ctlapp.App) (ctlapp.Change, error) {
+	if (len(o.ToChange) > 0) == (o.Steps > 0) {
+		return nil, fmt.Errorf("Expected either --to-change or --steps to be specified")
+	}
+
+	if len(o.ToChange) > 0 {
+		changes, err := app.Changes()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, change := range changes {
+			if change.Name() == o.ToChange {
+				if change.Meta().Partial {
+					return nil, fmt.Errorf("App change '%s' (app: %s) does not record entire app (e.g. it was deployed "+
+						"with filters or --patch, or Secret values were masked), hence cannot be rolled back to", o.ToChange, app.Name())
+				}
+				return change, nil
+			}
+		}
+		return nil, fmt.Errorf("App change '%s' (app: %s) does not exist", o.ToChange, app.Name())
+	}
+
+	return successfulAppChange(app, o.Steps)
+}
+
+// successfulAppChange returns successful app change that is given
+// number of steps back from the last successful app change.
+// Partial app changes are skipped since they do not represent entire app.
+func successfulAppChange(app ctlapp.App, steps int) (ctlapp.Change, error) {
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	var successfulChanges []ctlapp.Change
+
+	for _, change := range changes {
+		succeeded := change.Meta().Successful
+		if succeeded != nil && *succeeded && !change.Meta().Partial {
+			successfulChanges = append(successfulChanges, change)
+		}
+	}
+
+	// Last successful change represents current state of the app
+	if steps >= len(successfulChanges) {
+		return nil, fmt.Errorf("Expected to find at least %d successful non-partial app changes, but found %d",
+			steps+1, len(successfulChanges))
+	}
+
+	return successfulChanges[len(successfulChanges)-1-steps], nil
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"github.com/spf13/cobra"
+)
+
+const (
+	appHealthHealthy     = "healthy"
+	appHealthProgressing = "progressing"
+	appHealthFailed      = "failed"
+)
+
+type StatusOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags            AppFlags
+	ResourceFilterFlags cmdtools.ResourceFilterFlags
+	ResourceTypesFlags  ResourceTypesFlags
+}
+
+func NewStatusOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *StatusOptions {
+	return &StatusOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewStatusCmd(o *StatusOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status",
+		Aliases: []string{"st"},
+		Short:   "Show app health",
+		Long:    "Show app health (exits with non-zero exit code if any resource is failing)",
+		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Annotations: map[string]string{
+			cmdcore.AppHelpGroup.Key: cmdcore.AppHelpGroup.Value,
+		},
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.ResourceFilterFlags.Set(cmd)
+	o.ResourceTypesFlags.Set(cmd)
+	return cmd
+}
+
+func (o *StatusOptions) Run() error {
+	app, supportObjs, err := AppFactory(o.depsFactory, o.AppFlags, o.ResourceTypesFlags, o.logger)
+	if err != nil {
+		return err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return err
+	}
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, supportObjs.IdentifiedResources, o.logger)
+
+	resources, err := labeledResources.All()
+	if err != nil {
+		return err
+	}
+
+	resourceFilter, err := o.ResourceFilterFlags.ResourceFilter()
+	if err != nil {
+		return err
+	}
+
+	var statuses []ResourceStatus
+
+	for _, res := range resourceFilter.Apply(resources) {
+		// Resources created by cluster (e.g. Pods) are
+		// evaluated as associated resources of their parents
+		if res.Transient() {
+			continue
+		}
+
+		associatedRs, err := labeledResources.GetAssociated(res)
+		if err != nil {
+			return err
+		}
+
+		state, _, err := ctlcap.NewConvergedResource(res, associatedRs).IsDoneApplying()
+
+		statuses = append(statuses, ResourceStatus{res, ctlcap.NewDoneApplyStateUI(state, err)})
+	}
+
+	health := o.health(statuses)
+
+	AppStatusView{Source: fmt.Sprintf("app '%s'", app.Name()), Statuses: statuses}.Print(o.ui)
+
+	o.ui.PrintLinef("Status: %s", health)
+
+	if health == appHealthFailed {
+		return fmt.Errorf("App '%s' (namespace: %s) has failing resources", app.Name(), o.AppFlags.NamespaceFlags.Name)
+	}
+
+	return nil
+}
+
+func (o *StatusOptions) health(statuses []ResourceStatus) string {
+	health := appHealthHealthy
+
+	for _, status := range statuses {
+		switch status.State.State {
+		case "ok":
+			// do nothing
+		case "ongoing":
+			health = appHealthProgressing
+		default:
+			return appHealthFailed
+		}
+	}
+
+	return health
+}
+
+type ResourceStatus struct {
+	Resource ctlres.Resource
+	State    ctlcap.DoneApplyStateUI
+}
+
+type AppStatusView struct {
+	Source   string
+	Statuses []ResourceStatus
+}
+
+func (v AppStatusView) Print(ui ui.UI) {
+	table := uitable.Table{
+		Title:   fmt.Sprintf("Resources in %s", v.Source),
+		Content: "resources",
+
+		Header: []uitable.Header{
+			uitable.NewHeader("Namespace"),
+			uitable.NewHeader("Name"),
+			uitable.NewHeader("Kind"),
+			uitable.NewHeader("Health"),
+			uitable.NewHeader("Message"),
+		},
+
+		SortBy: []uitable.ColumnSort{
+			{Column: 0, Asc: true},
+			{Column: 1, Asc: true},
+			{Column: 2, Asc: true},
+		},
+	}
+
+	for _, status := range v.Statuses {
+		table.Rows = append(table.Rows, []uitable.Value{
+			cmdcore.NewValueNamespace(status.Resource.Namespace()),
+			uitable.NewValueString(status.Resource.Name()),
+			uitable.NewValueString(status.Resource.Kind()),
+			uitable.ValueFmt{
+				V:     uitable.NewValueString(status.State.State),
+				Error: status.State.Error,
+			},
+			uitable.NewValueString(status.State.Message),
+		})
+	}
+
+	ui.PrintTable(table)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	"github.com/spf13/cobra"
+)
+
+type UnlockOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags AppFlags
+	Force    bool
+}
+
+func NewUnlockOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *UnlockOptions {
+	return &UnlockOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewUnlockCmd(o *UnlockOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Release app deploy lock",
+		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Release expired deploy lock of app 'app1'
+  kapp app unlock -a app1
+
+  # Break deploy lock of app 'app1' even if it has not expired yet
+  kapp app unlock -a app1 --force`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	cmd.Flags().BoolVar(&o.Force, "force", false, "Release lock even if it has not expired yet")
+	return cmd
+}
+
+func (o *UnlockOptions) Run() error {
+	app, _, err := AppFactory(o.depsFactory, o.AppFlags, ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	lock, err := app.CurrentLock()
+	if err != nil {
+		return err
+	}
+
+	if lock == nil {
+		o.ui.PrintLinef("App '%s' (namespace: %s) is not locked", app.Name(), o.AppFlags.NamespaceFlags.Name)
+		return nil
+	}
+
+	if !lock.Expired() && !o.Force {
+		return fmt.Errorf("App '%s' (namespace: %s) is locked by %s since %s and lock has not expired yet "+
+			"(expires at %s, hint: use --force to release it)", app.Name(), o.AppFlags.NamespaceFlags.Name,
+			lock.Holder, lock.StartedAt, lock.ExpiresAt)
+	}
+
+	o.ui.PrintLinef("Releasing lock of app '%s' (namespace: %s) held by %s since %s",
+		app.Name(), o.AppFlags.NamespaceFlags.Name, lock.Holder, lock.StartedAt)
+
+	err = o.ui.AskForConfirmation()
+	if err != nil {
+		return err
+	}
+
+	return app.ForceUnlock()
+}
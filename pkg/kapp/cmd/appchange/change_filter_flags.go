@@ -0,0 +1,152 @@
+package appchange
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	"github.com/spf13/cobra"
+)
+
+type ChangeFilterFlags struct {
+	Meta     []string
+	User     string
+	Hostname string
+
+	Successful bool
+	Failed     bool
+
+	StartedAfter  string
+	StartedBefore string
+
+	Description string
+}
+
+func (s *ChangeFilterFlags) Set(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&s.Meta, "filter-meta", nil, "Set app change metadata to match (format: key=val) (can repeat)")
+	cmd.Flags().StringVar(&s.User, "filter-user", "", "Set user that made app change")
+	cmd.Flags().StringVar(&s.Hostname, "filter-hostname", "", "Set hostname from which app change was made")
+
+	cmd.Flags().BoolVar(&s.Successful, "filter-successful", false, "Show only successful app changes")
+	cmd.Flags().BoolVar(&s.Failed, "filter-failed", false, "Show only failed app changes")
+
+	cmd.Flags().StringVar(&s.StartedAfter, "filter-started-after", "",
+		"Show app changes started after given time (format: RFC3339 time, or duration like 24h relative to now)")
+	cmd.Flags().StringVar(&s.StartedBefore, "filter-started-before", "",
+		"Show app changes started before given time (format: RFC3339 time, or duration like 24h relative to now)")
+
+	cmd.Flags().StringVar(&s.Description, "filter-description", "", "Show app changes with description containing given string")
+}
+
+func (s *ChangeFilterFlags) ChangeFilter() (ChangeFilter, error) {
+	if s.Successful && s.Failed {
+		return ChangeFilter{}, fmt.Errorf("Expected only one of --filter-successful or --filter-failed to be specified")
+	}
+
+	filter := ChangeFilter{
+		Meta:        map[string]string{},
+		User:        s.User,
+		Hostname:    s.Hostname,
+		Description: s.Description,
+	}
+
+	for _, val := range s.Meta {
+		pieces := strings.SplitN(val, "=", 2)
+		if len(pieces) != 2 {
+			return ChangeFilter{}, fmt.Errorf("Expected app change metadata filter to be in 'key=val' format")
+		}
+		filter.Meta[pieces[0]] = pieces[1]
+	}
+
+	if s.Successful || s.Failed {
+		successful := s.Successful
+		filter.Successful = &successful
+	}
+
+	var err error
+
+	filter.StartedAfter, err = s.parseTime(s.StartedAfter)
+	if err != nil {
+		return ChangeFilter{}, fmt.Errorf("Parsing --filter-started-after: %s", err)
+	}
+
+	filter.StartedBefore, err = s.parseTime(s.StartedBefore)
+	if err != nil {
+		return ChangeFilter{}, fmt.Errorf("Parsing --filter-started-before: %s", err)
+	}
+
+	return filter, nil
+}
+
+func (s *ChangeFilterFlags) parseTime(val string) (time.Time, error) {
+	if len(val) == 0 {
+		return time.Time{}, nil
+	}
+
+	if dur, err := time.ParseDuration(val); err == nil {
+		return time.Now().UTC().Add(-dur), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Expected value '%s' to be either RFC3339 time or duration", val)
+	}
+
+	return t, nil
+}
+
+type ChangeFilter struct {
+	Meta     map[string]string
+	User     string
+	Hostname string
+
+	// Successful (if non-nil) matches finished changes with given result
+	Successful *bool
+
+	StartedAfter  time.Time
+	StartedBefore time.Time
+
+	Description string
+}
+
+func (f ChangeFilter) Apply(changes []ctlapp.Change) []ctlapp.Change {
+	var result []ctlapp.Change
+	for _, change := range changes {
+		if f.Matches(change) {
+			result = append(result, change)
+		}
+	}
+	return result
+}
+
+func (f ChangeFilter) Matches(change ctlapp.Change) bool {
+	meta := change.Meta()
+
+	for key, val := range f.Meta {
+		if actualVal, found := meta.Custom[key]; !found || actualVal != val {
+			return false
+		}
+	}
+	if len(f.User) > 0 && meta.User != f.User {
+		return false
+	}
+	if len(f.Hostname) > 0 && meta.Hostname != f.Hostname {
+		return false
+	}
+	if f.Successful != nil {
+		if meta.Successful == nil || *meta.Successful != *f.Successful {
+			return false
+		}
+	}
+	if !f.StartedAfter.IsZero() && !meta.StartedAt.After(f.StartedAfter) {
+		return false
+	}
+	if !f.StartedBefore.IsZero() && !meta.StartedAt.Before(f.StartedBefore) {
+		return false
+	}
+	if len(f.Description) > 0 && !strings.Contains(meta.Description, f.Description) {
+		return false
+	}
+	return true
+}
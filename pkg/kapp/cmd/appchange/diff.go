@@ -0,0 +1,115 @@
+package appchange
+
+import (
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	cmdapp "github.com/k14s/kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"github.com/spf13/cobra"
+)
+
+type DiffOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags       cmdapp.AppFlags
+	DiffFlags      cmdtools.DiffFlags
+	FromChangeName string
+	ToChangeName   string
+}
+
+func NewDiffOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DiffOptions {
+	return &DiffOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewDiffCmd(o *DiffOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "diff",
+		Aliases: []string{"d"},
+		Short:   "Diff resources applied in two app changes",
+		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Show summary of differences between two app changes
+  kapp app-change diff -a app1 --from app1-change-abc12 --to app1-change-def34
+
+  # Show detailed differences between two app changes
+  kapp app-change diff -a app1 --from app1-change-abc12 --to app1-change-def34 -c`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	o.DiffFlags.SetWithPrefix("", cmd)
+	cmd.Flags().StringVar(&o.FromChangeName, "from", "", "Set app change name to diff from")
+	cmd.Flags().StringVar(&o.ToChangeName, "to", "", "Set app change name to diff to")
+	return cmd
+}
+
+func (o *DiffOptions) Run() error {
+	app, _, err := cmdapp.AppFactory(o.depsFactory, o.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	fromResources, err := o.changeResources(app, o.FromChangeName)
+	if err != nil {
+		return err
+	}
+
+	toResources, err := o.changeResources(app, o.ToChangeName)
+	if err != nil {
+		return err
+	}
+
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		return err
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, o.DiffFlags.ChangeOpts(conf))
+
+	changes, err := ctldiff.NewChangeSet(fromResources, toResources, o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
+	if err != nil {
+		return err
+	}
+
+	var changeViews []ctlcap.ChangeView
+
+	for _, change := range changes {
+		changeViews = append(changeViews, cmdtools.NewDiffChangeView(change))
+	}
+
+	changeViews = o.DiffFlags.FilterChangeViews(changeViews)
+
+	changeSetView := ctlcap.NewChangeSetView(changeViews, o.DiffFlags.ChangeSetViewOpts)
+	err = changeSetView.Print(o.ui)
+	if err != nil {
+		return err
+	}
+
+	return o.DiffFlags.ExitStatusErr(changeSetView.HasChanges())
+}
+
+func (o *DiffOptions) changeResources(app ctlapp.App, name string) ([]ctlres.Resource, error) {
+	change, err := FindChange(app, name)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := change.Resources()
+	if err != nil {
+		return nil, err
+	}
+
+	// Recorded config resources are not part of the app
+	resources, _, err = ctlconf.NewConfFromResources(resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
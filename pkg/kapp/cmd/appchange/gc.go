@@ -41,7 +41,7 @@ func (o *GCOptions) Run() error {
 	}
 
 	reviewFunc := func(changesToDelete []ctlapp.Change) error {
-		AppChangesTable{"App changes to delete", changesToDelete}.Print(o.ui)
+		AppChangesTable{Title: "App changes to delete", Changes: changesToDelete}.Print(o.ui)
 
 		err = o.ui.AskForConfirmation()
 		if err != nil {
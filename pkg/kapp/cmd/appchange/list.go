@@ -1,6 +1,8 @@
 package appchange
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cppforlife/go-cli-ui/ui"
@@ -17,7 +19,12 @@ type ListOptions struct {
 	depsFactory cmdcore.DepsFactory
 	logger      logger.Logger
 
-	AppFlags cmdapp.AppFlags
+	AppFlags          cmdapp.AppFlags
+	ChangeFilterFlags ChangeFilterFlags
+	AllApps           bool
+
+	// FullMeta is set when JSON output is requested
+	FullMeta bool
 }
 
 func NewListOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ListOptions {
@@ -29,13 +36,36 @@ func NewListCmd(o *ListOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Comman
 		Use:     "list",
 		Aliases: []string{"l", "ls"},
 		Short:   "List app changes",
-		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			o.FullMeta, _ = cmd.Flags().GetBool("json")
+			return o.Run()
+		},
+		Example: `
+  # List app changes for app 'app1'
+  kapp app-change list -a app1
+
+  # List failed app changes made in the last 24 hours
+  kapp app-change list -a app1 --filter-failed --filter-started-after 24h
+
+  # List app changes for all apps in a namespace made from particular commit
+  kapp app-change list --all-apps --filter-meta commit=abc123`,
 	}
 	o.AppFlags.Set(cmd, flagsFactory)
+	o.ChangeFilterFlags.Set(cmd)
+	cmd.Flags().BoolVar(&o.AllApps, "all-apps", false, "List app changes for all apps in a namespace")
 	return cmd
 }
 
 func (o *ListOptions) Run() error {
+	changeFilter, err := o.ChangeFilterFlags.ChangeFilter()
+	if err != nil {
+		return err
+	}
+
+	if o.AllApps {
+		return o.listAllApps(changeFilter)
+	}
+
 	app, _, err := cmdapp.AppFactory(o.depsFactory, o.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
 	if err != nil {
 		return err
@@ -46,7 +76,52 @@ func (o *ListOptions) Run() error {
 		return err
 	}
 
-	AppChangesTable{"App changes", changes}.Print(o.ui)
+	AppChangesTable{
+		Title:    "App changes",
+		Changes:  changeFilter.Apply(changes),
+		FullMeta: o.FullMeta,
+	}.Print(o.ui)
+
+	return nil
+}
+
+func (o *ListOptions) listAllApps(changeFilter ChangeFilter) error {
+	if len(o.AppFlags.Name) > 0 {
+		return fmt.Errorf("Expected app name to not be specified together with --all-apps")
+	}
+
+	supportObjs, err := cmdapp.AppFactoryClients(o.depsFactory, o.AppFlags.NamespaceFlags,
+		o.AppFlags.AppStateFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	apps, err := supportObjs.Apps.List(nil)
+	if err != nil {
+		return err
+	}
+
+	var allChanges []ctlapp.Change
+	changeApps := map[string]string{}
+
+	for _, app := range apps {
+		changes, err := app.Changes()
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changeFilter.Apply(changes) {
+			allChanges = append(allChanges, change)
+			changeApps[change.Name()] = app.Name()
+		}
+	}
+
+	AppChangesTable{
+		Title:      fmt.Sprintf("App changes in namespace '%s'", o.AppFlags.NamespaceFlags.Name),
+		Changes:    allChanges,
+		ChangeApps: changeApps,
+		FullMeta:   o.FullMeta,
+	}.Print(o.ui)
 
 	return nil
 }
@@ -54,44 +129,83 @@ func (o *ListOptions) Run() error {
 type AppChangesTable struct {
 	Title   string
 	Changes []ctlapp.Change
+
+	// ChangeApps (if non-nil) maps change names to app names
+	ChangeApps map[string]string
+	// FullMeta includes complete app change metadata
+	FullMeta bool
 }
 
 func (t AppChangesTable) Print(ui ui.UI) {
+	appHeader := uitable.NewHeader("App")
+	appHeader.Hidden = t.ChangeApps == nil
+
 	nsHeader := uitable.NewHeader("Namespaces")
 	nsHeader.Hidden = true
 
+	metaHeader := uitable.NewHeader("Change Meta")
+	metaHeader.Hidden = !t.FullMeta
+
 	table := uitable.Table{
 		Title:   t.Title,
 		Content: "app changes",
 
 		Header: []uitable.Header{
+			appHeader,
 			uitable.NewHeader("Name"),
 			uitable.NewHeader("Started At"),
 			uitable.NewHeader("Finished At"),
+			uitable.NewHeader("Duration"),
 			uitable.NewHeader("Successful"),
 			uitable.NewHeader("Description"),
 			nsHeader,
+			uitable.NewHeader("User"),
+			uitable.NewHeader("Kapp Version"),
+			uitable.NewHeader("Meta"),
+			metaHeader,
 		},
 
 		SortBy: []uitable.ColumnSort{
-			{Column: 1, Asc: false},
-			{Column: 0, Asc: true}, // in case start time are same
+			{Column: 2, Asc: false},
+			{Column: 1, Asc: true}, // in case start time are same
 		},
 	}
 
 	for _, change := range t.Changes {
 		table.Rows = append(table.Rows, []uitable.Value{
+			uitable.NewValueString(t.ChangeApps[change.Name()]),
 			uitable.NewValueString(change.Name()),
 			uitable.NewValueTime(change.Meta().StartedAt),
 			uitable.NewValueTime(change.Meta().FinishedAt),
+			cmdcore.NewValueDuration(change.Meta().StartedAt, change.Meta().FinishedAt),
 			uitable.ValueFmt{
 				V:     cmdcore.NewValueUnknownBool(change.Meta().Successful),
 				Error: change.Meta().Successful == nil || *change.Meta().Successful != true,
 			},
 			uitable.NewValueString(change.Meta().Description),
 			uitable.NewValueString(strings.Join(change.Meta().Namespaces, ",")),
+			uitable.NewValueString(t.origin(change.Meta())),
+			uitable.NewValueString(change.Meta().KappVersion),
+			uitable.NewValueStrings(t.customMeta(change.Meta())),
+			uitable.NewValueString(change.Meta().AsString()),
 		})
 	}
 
 	ui.PrintTable(table)
 }
+
+func (AppChangesTable) origin(meta ctlapp.ChangeMeta) string {
+	if len(meta.User) == 0 && len(meta.Hostname) == 0 {
+		return ""
+	}
+	return meta.User + "@" + meta.Hostname
+}
+
+func (AppChangesTable) customMeta(meta ctlapp.ChangeMeta) []string {
+	var result []string
+	for key, val := range meta.Custom {
+		result = append(result, fmt.Sprintf("%s=%s", key, val))
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,101 @@
+package appchange
+
+import (
+	"fmt"
+
+	"github.com/cppforlife/go-cli-ui/ui"
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	cmdapp "github.com/k14s/kapp/pkg/kapp/cmd/app"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	cmdtools "github.com/k14s/kapp/pkg/kapp/cmd/tools"
+	"github.com/k14s/kapp/pkg/kapp/logger"
+	"github.com/spf13/cobra"
+)
+
+type ShowOptions struct {
+	ui          ui.UI
+	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
+
+	AppFlags   cmdapp.AppFlags
+	ChangeName string
+	Raw        bool
+}
+
+func NewShowOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *ShowOptions {
+	return &ShowOptions{ui: ui, depsFactory: depsFactory, logger: logger}
+}
+
+func NewShowCmd(o *ShowOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "show",
+		Aliases: []string{"s"},
+		Short:   "Show resources applied in app change",
+		RunE:    func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Example: `
+  # Show resources applied in app change
+  kapp app-change show -a app1 --change app1-change-abc12
+
+  # Output resources applied in app change as YAML
+  kapp app-change show -a app1 --change app1-change-abc12 --raw`,
+	}
+	o.AppFlags.Set(cmd, flagsFactory)
+	cmd.Flags().StringVar(&o.ChangeName, "change", "", "Set app change name")
+	cmd.Flags().BoolVar(&o.Raw, "raw", false, "Output raw YAML resource content")
+	return cmd
+}
+
+func (o *ShowOptions) Run() error {
+	app, _, err := cmdapp.AppFactory(o.depsFactory, o.AppFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	if err != nil {
+		return err
+	}
+
+	change, err := FindChange(app, o.ChangeName)
+	if err != nil {
+		return err
+	}
+
+	resources, err := change.Resources()
+	if err != nil {
+		return err
+	}
+
+	if o.Raw {
+		for _, res := range resources {
+			resBs, err := res.AsYAMLBytes()
+			if err != nil {
+				return err
+			}
+
+			o.ui.PrintBlock(append([]byte("---\n"), resBs...))
+		}
+		return nil
+	}
+
+	AppChangesTable{Title: "App change", Changes: []ctlapp.Change{change}}.Print(o.ui)
+
+	source := fmt.Sprintf("app change '%s'", change.Name())
+	cmdtools.InspectView{Source: source, Resources: resources, Sort: true}.Print(o.ui)
+
+	return nil
+}
+
+func FindChange(app ctlapp.App, name string) (ctlapp.Change, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("Expected app change name to be non-empty")
+	}
+
+	changes, err := app.Changes()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if change.Name() == name {
+			return change, nil
+		}
+	}
+
+	return nil, fmt.Errorf("App change '%s' (app: %s) does not exist", name, app.Name())
+}
@@ -1,17 +1,20 @@
 package appgroup
 
 import (
+	cmdapp "github.com/k14s/kapp/pkg/kapp/cmd/app"
 	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
 	"github.com/spf13/cobra"
 )
 
 type AppGroupFlags struct {
 	NamespaceFlags cmdcore.NamespaceFlags
+	AppStateFlags  cmdapp.AppStateFlags
 	Name           string
 }
 
 func (s *AppGroupFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
 	s.NamespaceFlags.Set(cmd, flagsFactory)
+	s.AppStateFlags.Set(cmd)
 
 	cmd.Flags().StringVarP(&s.Name, "group", "g", "", "Set app group name")
 }
@@ -45,7 +45,8 @@ func NewDeleteCmd(o *DeleteOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 }
 
 func (o *DeleteOptions) Run() error {
-	supportObjs, err := cmdapp.AppFactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	supportObjs, err := cmdapp.AppFactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags,
+		o.AppGroupFlags.AppStateFlags, cmdapp.ResourceTypesFlags{}, o.logger)
 	if err != nil {
 		return err
 	}
@@ -73,6 +74,7 @@ func (o *DeleteOptions) deleteApp(name string) error {
 	deleteOpts.AppFlags = cmdapp.AppFlags{
 		Name:           name,
 		NamespaceFlags: o.AppGroupFlags.NamespaceFlags,
+		AppStateFlags:  o.AppGroupFlags.AppStateFlags,
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.ApplyFlags
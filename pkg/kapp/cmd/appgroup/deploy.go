@@ -30,6 +30,7 @@ type DeployAppFlags struct {
 	DeleteApplyFlags    cmdapp.ApplyFlags
 	DeployFlags         cmdapp.DeployFlags
 	LabelFlags          cmdapp.LabelFlags
+	ChangeMetaFlags     cmdapp.ChangeMetaFlags
 }
 
 func NewDeployOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DeployOptions {
@@ -50,6 +51,7 @@ func NewDeployCmd(o *DeployOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Co
 	o.AppFlags.ApplyFlags.SetWithDefaults("", cmdapp.ApplyFlagsDeployDefaults, cmd)
 	o.AppFlags.DeleteApplyFlags.SetWithDefaults("delete", cmdapp.ApplyFlagsDeleteDefaults, cmd)
 	o.AppFlags.DeployFlags.Set(cmd)
+	o.AppFlags.ChangeMetaFlags.Set(cmd)
 	return cmd
 }
 
@@ -69,7 +71,8 @@ func (o *DeployOptions) Run() error {
 		}
 	}
 
-	supportObjs, err := cmdapp.AppFactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags, cmdapp.ResourceTypesFlags{}, o.logger)
+	supportObjs, err := cmdapp.AppFactoryClients(o.depsFactory, o.AppGroupFlags.NamespaceFlags,
+		o.AppGroupFlags.AppStateFlags, cmdapp.ResourceTypesFlags{}, o.logger)
 	if err != nil {
 		return err
 	}
@@ -128,6 +131,7 @@ func (o *DeployOptions) deployApp(app appGroupApp) error {
 	deployOpts.AppFlags = cmdapp.AppFlags{
 		Name:           app.Name,
 		NamespaceFlags: o.AppGroupFlags.NamespaceFlags,
+		AppStateFlags:  o.AppGroupFlags.AppStateFlags,
 	}
 	deployOpts.FileFlags = cmdtools.FileFlags{
 		Files: []string{app.Path},
@@ -138,6 +142,7 @@ func (o *DeployOptions) deployApp(app appGroupApp) error {
 	deployOpts.DeployFlags = o.AppFlags.DeployFlags
 
 	deployOpts.LabelFlags = o.AppFlags.LabelFlags
+	deployOpts.ChangeMetaFlags = o.AppFlags.ChangeMetaFlags
 	deployOpts.LabelFlags.Labels = append(
 		deployOpts.LabelFlags.Labels,
 		fmt.Sprintf("%s=%s", appGroupAnnKey, o.AppGroupFlags.Name))
@@ -153,6 +158,7 @@ func (o *DeployOptions) deleteApp(name string) error {
 	deleteOpts.AppFlags = cmdapp.AppFlags{
 		Name:           name,
 		NamespaceFlags: o.AppGroupFlags.NamespaceFlags,
+		AppStateFlags:  o.AppGroupFlags.AppStateFlags,
 	}
 	deleteOpts.DiffFlags = o.AppFlags.DiffFlags
 	deleteOpts.ApplyFlags = o.AppFlags.DeleteApplyFlags
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"time"
+
+	uitable "github.com/cppforlife/go-cli-ui/ui/table"
+)
+
+type ValueDuration struct {
+	Start time.Time
+	End   time.Time
+}
+
+var _ uitable.Value = ValueDuration{}
+
+func NewValueDuration(start, end time.Time) ValueDuration {
+	return ValueDuration{Start: start, End: end}
+}
+
+func (t ValueDuration) String() string {
+	if t.Start.IsZero() || t.End.IsZero() {
+		return ""
+	}
+	return t.End.Sub(t.Start).Round(time.Second).String()
+}
+
+func (t ValueDuration) Value() uitable.Value { return t }
+
+func (t ValueDuration) Compare(other uitable.Value) int {
+	thisD := t.End.Sub(t.Start)
+	otherD := other.(ValueDuration).End.Sub(other.(ValueDuration).Start)
+	switch {
+	case thisD == otherD:
+		return 0
+	case thisD < otherD:
+		return -1
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,7 @@
+package core
+
+// ExitStatus is implemented by errors that
+// require specific process exit status
+type ExitStatus interface {
+	ExitStatus() int
+}
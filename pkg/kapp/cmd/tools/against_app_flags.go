@@ -0,0 +1,30 @@
+package tools
+
+import (
+	"os"
+
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	"github.com/spf13/cobra"
+)
+
+type AgainstAppFlags struct {
+	NamespaceFlags cmdcore.NamespaceFlags
+	Name           string
+	StateKind      string
+}
+
+func (s *AgainstAppFlags) Set(cmd *cobra.Command, flagsFactory cmdcore.FlagsFactory) {
+	s.NamespaceFlags.Set(cmd, flagsFactory)
+
+	cmd.Flags().StringVar(&s.Name, "against-app", "", "Set app name (or label selector) to diff against instead of files2 (format: name, label:key=val, !key)")
+
+	// Cannot reuse app state flags from app package since it depends on this package
+	kind := os.Getenv("KAPP_APP_STATE_KIND")
+	if len(kind) == 0 {
+		kind = ctlapp.StateKindConfigMap
+	}
+
+	cmd.Flags().StringVar(&s.StateKind, "app-state-kind", kind,
+		"Set kind of resource used to store app state (configmap, secret) ($KAPP_APP_STATE_KIND)")
+}
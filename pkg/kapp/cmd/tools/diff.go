@@ -1,10 +1,15 @@
 package tools
 
 import (
+	"fmt"
+
 	"github.com/cppforlife/go-cli-ui/ui"
+	ctlapp "github.com/k14s/kapp/pkg/kapp/app"
 	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
 	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	"github.com/k14s/kapp/pkg/kapp/logger"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
 	"github.com/spf13/cobra"
 )
@@ -12,29 +17,42 @@ import (
 type DiffOptions struct {
 	ui          ui.UI
 	depsFactory cmdcore.DepsFactory
+	logger      logger.Logger
 
-	FileFlags  FileFlags
-	FileFlags2 FileFlags2
-	DiffFlags  DiffFlags
+	FileFlags       FileFlags
+	FileFlags2      FileFlags2
+	AgainstAppFlags AgainstAppFlags
+	DiffFlags       DiffFlags
 }
 
-func NewDiffOptions(ui ui.UI, depsFactory cmdcore.DepsFactory) *DiffOptions {
-	return &DiffOptions{ui: ui, depsFactory: depsFactory}
+func NewDiffOptions(ui ui.UI, depsFactory cmdcore.DepsFactory, logger logger.Logger) *DiffOptions {
+	return &DiffOptions{ui: ui, depsFactory: depsFactory, logger: logger}
 }
 
 func NewDiffCmd(o *DiffOptions, flagsFactory cmdcore.FlagsFactory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "diff",
-		Short: "Diff files against files2",
-		RunE:  func(_ *cobra.Command, _ []string) error { return o.Run() },
+		Short: "Diff files against files2 (or against app)",
+		Example: `
+  # Diff two sets of rendered files
+  kapp tools diff -f new/ --file2 old/
+
+  # Diff files against resources of deployed app
+  kapp tools diff -f config/ --against-app app1`,
+		RunE: func(_ *cobra.Command, _ []string) error { return o.Run() },
 	}
 	o.FileFlags.Set(cmd)
 	o.FileFlags2.Set(cmd)
+	o.AgainstAppFlags.Set(cmd, flagsFactory)
 	o.DiffFlags.SetWithPrefix("", cmd)
 	return cmd
 }
 
 func (o *DiffOptions) Run() error {
+	if len(o.AgainstAppFlags.Name) > 0 && len(o.FileFlags2.Files) > 0 {
+		return fmt.Errorf("Expected only one of --file2 or --against-app to be specified")
+	}
+
 	newResources, err := o.fileResources(o.FileFlags.Files)
 	if err != nil {
 		return err
@@ -45,9 +63,36 @@ func (o *DiffOptions) Run() error {
 		return err
 	}
 
-	changeFactory := ctldiff.NewChangeFactory(nil, nil)
+	newResources, newConf, err := ctlconf.NewConfFromResources(newResources)
+	if err != nil {
+		return err
+	}
 
-	changes, err := ctldiff.NewChangeSet(existingResources, newResources, o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
+	existingResources, existingConf, err := ctlconf.NewConfFromResources(existingResources)
+	if err != nil {
+		return err
+	}
+
+	// Config found in either set of files applies to both sets
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(
+		append(newConf.Resources(), existingConf.Resources()...))
+	if err != nil {
+		return err
+	}
+
+	if len(o.AgainstAppFlags.Name) > 0 {
+		newResources, existingResources, err = o.appResources(newResources, conf)
+		if err != nil {
+			return err
+		}
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(conf.RebaseMods(),
+		conf.DiffAgainstLastAppliedFieldExclusionMods(), o.DiffFlags.ChangeOpts(conf))
+
+	changes, err := ctldiff.NewChangeSetWithTemplates(
+		existingResources, newResources, conf.TemplateRules(),
+		o.DiffFlags.ChangeSetOpts, changeFactory).Calculate()
 	if err != nil {
 		return err
 	}
@@ -55,12 +100,81 @@ func (o *DiffOptions) Run() error {
 	var changeViews []ctlcap.ChangeView
 
 	for _, change := range changes {
-		changeViews = append(changeViews, DiffChangeView{change})
+		changeViews = append(changeViews, NewDiffChangeView(change))
 	}
 
-	ctlcap.NewChangeSetView(changeViews, o.DiffFlags.ChangeSetViewOpts).Print(o.ui)
+	changeViews = o.DiffFlags.FilterChangeViews(changeViews)
+
+	changeSetView := ctlcap.NewChangeSetView(changeViews, o.DiffFlags.ChangeSetViewOpts)
+	err = changeSetView.Print(o.ui)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return o.DiffFlags.ExitStatusErr(changeSetView.HasChanges())
+}
+
+// appResources prepares new resources the same way deploy does and finds
+// app's resources to diff against; only read access to the cluster is necessary
+func (o *DiffOptions) appResources(newResources []ctlres.Resource,
+	conf ctlconf.Conf) ([]ctlres.Resource, []ctlres.Resource, error) {
+
+	coreClient, err := o.depsFactory.CoreClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dynamicClient, err := o.depsFactory.DynamicClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stateStorage, err := ctlapp.NewStateStorage(o.AgainstAppFlags.StateKind, coreClient)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nsName := o.AgainstAppFlags.NamespaceFlags.Name
+
+	resTypes := ctlres.NewResourceTypesImpl(coreClient, ctlres.ResourceTypesImplOpts{})
+	identifiedResources := ctlres.NewIdentifiedResources(
+		coreClient, dynamicClient, resTypes, []string{nsName}, o.logger)
+
+	app, err := ctlapp.NewApps(nsName, stateStorage, identifiedResources, o.logger).Find(o.AgainstAppFlags.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labelSelector, err := app.LabelSelector()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prep := ctlapp.NewPreparation(resTypes, ctlapp.PrepareResourcesOpts{DefaultNamespace: nsName})
+
+	newResources, err = prep.PrepareResources(newResources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labeledResources := ctlres.NewLabeledResources(labelSelector, identifiedResources, o.logger)
+
+	err = labeledResources.Prepare(newResources, conf.OwnershipLabelMods(),
+		conf.LabelScopingMods(), conf.AdditionalLabels())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matchingOpts := ctlres.AllAndMatchingOpts{
+		BlacklistedResourcesByLabelKeys: []string{ctlapp.KappIsAppLabelKey},
+	}
+
+	existingResources, err := labeledResources.AllAndMatching(newResources, matchingOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newResources, existingResources, nil
 }
 
 func (o *DiffOptions) fileResources(files []string) ([]ctlres.Resource, error) {
@@ -91,6 +205,8 @@ type DiffChangeView struct {
 
 var _ ctlcap.ChangeView = DiffChangeView{}
 
+func NewDiffChangeView(change ctldiff.Change) DiffChangeView { return DiffChangeView{change} }
+
 func (v DiffChangeView) Resource() ctlres.Resource         { return v.change.NewOrExistingResource() }
 func (v DiffChangeView) ExistingResource() ctlres.Resource { return v.change.ExistingResource() }
 
@@ -113,3 +229,4 @@ func (v DiffChangeView) ApplyOp() ctlcap.ClusterChangeApplyOp {
 func (v DiffChangeView) WaitOp() ctlcap.ClusterChangeWaitOp { return ctlcap.ClusterChangeWaitOpNoop }
 
 func (v DiffChangeView) TextDiff() ctldiff.TextDiff { return v.change.TextDiff() }
+func (v DiffChangeView) OpsDiff() ctldiff.OpsDiff   { return v.change.OpsDiff() }
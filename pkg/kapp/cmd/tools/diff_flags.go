@@ -1,7 +1,12 @@
 package tools
 
 import (
+	"fmt"
+	"strings"
+
 	ctlcap "github.com/k14s/kapp/pkg/kapp/clusterapply"
+	cmdcore "github.com/k14s/kapp/pkg/kapp/cmd/core"
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
 	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
 	"github.com/spf13/cobra"
 )
@@ -10,7 +15,14 @@ type DiffFlags struct {
 	ctlcap.ChangeSetViewOpts
 	ctldiff.ChangeSetOpts
 
-	Run bool
+	Run        bool
+	ExitStatus bool
+	Filter     *ctlcap.ChangeSetFilter
+	// FilterApply excludes filtered out changes from being applied
+	// (otherwise filter only affects which changes are shown)
+	FilterApply bool
+	// Structured aligns list items by merge keys before diffing
+	Structured bool
 }
 
 func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
@@ -19,10 +31,105 @@ func (s *DiffFlags) SetWithPrefix(prefix string, cmd *cobra.Command) {
 	}
 
 	cmd.Flags().BoolVar(&s.Run, prefix+"run", false, "Show diff and exit successfully without any further action")
+	cmd.Flags().BoolVar(&s.ExitStatus, prefix+"exit-status", false, "Return specific exit status based on presence of changes (0: no changes, 2: changes)")
 
 	cmd.Flags().BoolVar(&s.Summary, prefix+"summary", true, "Show diff summary")
 	cmd.Flags().BoolVarP(&s.Changes, prefix+"changes", "c", false, "Show changes")
 
+	s.Format = ctlcap.ChangeSetViewFormatText
+	cmd.Flags().Var(diffFormatFlag{&s.Format}, prefix+"format", "Set diff format (one of: "+diffFormatsString()+")")
+
+	cmd.Flags().Var(diffFilterFlag{&s.Filter}, prefix+"filter", `Set changes filter (example: {"and":[{"ops":["add","update"]},{"resource":{"kinds":["Deployment"]}}]})`)
+	cmd.Flags().BoolVar(&s.FilterApply, prefix+"filter-apply", false, "Set to only apply changes that match changes filter (otherwise filter only affects which changes are shown)")
+
 	cmd.Flags().IntVar(&s.Context, prefix+"context", 2, "Show number of lines around changed lines")
 	cmd.Flags().BoolVar(&s.AgainstLastApplied, prefix+"against-last-applied", true, "Show changes against last applied copy when possible")
+	cmd.Flags().BoolVar(&s.Structured, prefix+"structured", false, "Show changes per field path and match list items (e.g. containers) by merge keys so that reordering is not shown as a change")
+}
+
+// ChangeOpts returns options that control how resources are compared;
+// list items are only aligned by merge keys for structured diffing
+func (s *DiffFlags) ChangeOpts(conf ctlconf.Conf) ctldiff.ChangeOpts {
+	opts := ctldiff.ChangeOpts{MaskMods: conf.DiffMaskMods()}
+	if s.Structured {
+		opts.Structured = true
+		opts.ListMergeKeyMods = conf.ListMergeKeyMods()
+	}
+	return opts
+}
+
+// ExitStatusErr returns error with exit status 2 when
+// there are changes and exit status was requested
+func (s *DiffFlags) ExitStatusErr(hasChanges bool) error {
+	if s.ExitStatus && hasChanges {
+		return DiffExitStatus{}
+	}
+	return nil
+}
+
+// ApplyFilter returns filter that limits which changes are applied
+func (s *DiffFlags) ApplyFilter() *ctlcap.ChangeSetFilter {
+	if s.FilterApply {
+		return s.Filter
+	}
+	return nil
+}
+
+// FilterChangeViews returns change views that match diff filter
+func (s *DiffFlags) FilterChangeViews(changeViews []ctlcap.ChangeView) []ctlcap.ChangeView {
+	if s.Filter != nil {
+		return s.Filter.Apply(changeViews)
+	}
+	return changeViews
+}
+
+type DiffExitStatus struct{}
+
+var _ cmdcore.ExitStatus = DiffExitStatus{}
+
+func (DiffExitStatus) Error() string {
+	return "Exiting after diffing with pending changes (exit status 2)"
+}
+
+func (DiffExitStatus) ExitStatus() int { return 2 }
+
+type diffFormatFlag struct {
+	format *ctlcap.ChangeSetViewFormat
+}
+
+func (s diffFormatFlag) Set(val string) error {
+	for _, format := range ctlcap.ChangeSetViewFormats {
+		if string(format) == val {
+			*s.format = format
+			return nil
+		}
+	}
+	return fmt.Errorf("Expected diff format to be one of: %s", diffFormatsString())
+}
+
+func (s diffFormatFlag) Type() string   { return "string" }
+func (s diffFormatFlag) String() string { return string(*s.format) }
+
+type diffFilterFlag struct {
+	filter **ctlcap.ChangeSetFilter
+}
+
+func (s diffFilterFlag) Set(val string) error {
+	filter, err := ctlcap.NewChangeSetFilterFromString(val)
+	if err != nil {
+		return fmt.Errorf("Parsing diff filter: %s", err)
+	}
+	*s.filter = filter
+	return nil
+}
+
+func (s diffFilterFlag) Type() string   { return "string" }
+func (s diffFilterFlag) String() string { return "" } // default for usage
+
+func diffFormatsString() string {
+	var formats []string
+	for _, format := range ctlcap.ChangeSetViewFormats {
+		formats = append(formats, string(format))
+	}
+	return strings.Join(formats, ", ")
 }
@@ -26,6 +26,13 @@ func (s *ResourceFilterFlags) Set(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&s.bf, "filter", "", `Set filter (example: {"and":[{"not":{"resource":{"kinds":["foo%"]}}},{"resource":{"kinds":["!foo"]}}]})`)
 }
 
+// Empty indicates that no resource filters were specified
+func (s *ResourceFilterFlags) Empty() bool {
+	return len(s.age) == 0 && len(s.bf) == 0 && len(s.rf.Kinds) == 0 &&
+		len(s.rf.Namespaces) == 0 && len(s.rf.Names) == 0 &&
+		len(s.rf.KindNamespaces) == 0 && len(s.rf.KindNsNames) == 0
+}
+
 func (s *ResourceFilterFlags) ResourceFilter() (ctlres.ResourceFilter, error) {
 	createdAtBeforeTime, createdAtAfterTime, err := s.Times()
 	if err != nil {
@@ -7,12 +7,14 @@ import (
 )
 
 type Conf struct {
-	configs []Config
+	configs   []Config
+	resources []ctlres.Resource
 }
 
 func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf, error) {
 	var rsWithoutConfigs []ctlres.Resource
 	var configs []Config
+	var configRs []ctlres.Resource
 
 	for _, res := range resources {
 		if res.APIVersion() == configAPIVersion {
@@ -22,6 +24,7 @@ func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf,
 					return nil, Conf{}, err
 				}
 				configs = append(configs, config)
+				configRs = append(configRs, res)
 			} else {
 				errMsg := "Unexpected kind in resource '%s', wanted '%s'"
 				return nil, Conf{}, fmt.Errorf(errMsg, res.Description(), configKind)
@@ -31,7 +34,19 @@ func NewConfFromResources(resources []ctlres.Resource) ([]ctlres.Resource, Conf,
 		}
 	}
 
-	return rsWithoutConfigs, Conf{configs}, nil
+	return rsWithoutConfigs, Conf{configs, configRs}, nil
+}
+
+// Resources returns config resources that were
+// used to build conf (excluding default config)
+func (c Conf) Resources() []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range c.resources {
+		if res != defaultConfigRes {
+			result = append(result, res.DeepCopy())
+		}
+	}
+	return result
 }
 
 func (c Conf) RebaseMods() []ctlres.ResourceModWithMultiple {
@@ -44,6 +59,26 @@ func (c Conf) RebaseMods() []ctlres.ResourceModWithMultiple {
 	return mods
 }
 
+func (c Conf) ListMergeKeyMods() []ctlres.ResourceModWithMultiple {
+	var mods []ctlres.ResourceModWithMultiple
+	for _, config := range c.configs {
+		for _, rule := range config.ListMergeKeyRules {
+			mods = append(mods, rule.AsMods()...)
+		}
+	}
+	return mods
+}
+
+func (c Conf) DiffMaskMods() []ctlres.FieldMaskMod {
+	var mods []ctlres.FieldMaskMod
+	for _, config := range c.configs {
+		for _, rule := range config.DiffMaskRules {
+			mods = append(mods, rule.AsMods()...)
+		}
+	}
+	return mods
+}
+
 func (c Conf) DiffAgainstLastAppliedFieldExclusionMods() []ctlres.FieldRemoveMod {
 	var mods []ctlres.FieldRemoveMod
 	for _, config := range c.configs {
@@ -20,6 +20,8 @@ type Config struct {
 	OwnershipLabelRules []OwnershipLabelRule
 	LabelScopingRules   []LabelScopingRule
 	TemplateRules       []TemplateRule
+	ListMergeKeyRules   []ListMergeKeyRule
+	DiffMaskRules       []DiffMaskRule
 
 	AdditionalLabels                          map[string]string
 	DiffAgainstLastAppliedFieldExclusionRules []DiffAgainstLastAppliedFieldExclusionRule
@@ -47,6 +49,17 @@ type LabelScopingRule struct {
 	Path             ctlres.Path
 }
 
+type ListMergeKeyRule struct {
+	ResourceMatchers []ResourceMatcher
+	Path             ctlres.Path
+	MergeKey         string `json:"mergeKey"`
+}
+
+type DiffMaskRule struct {
+	ResourceMatchers []ResourceMatcher
+	Path             ctlres.Path
+}
+
 type TemplateRule struct {
 	ResourceMatchers  []ResourceMatcher
 	AffectedResources TemplateAffectedResources
@@ -126,6 +139,29 @@ func (r RebaseRule) AsMods() []ctlres.ResourceModWithMultiple {
 	return mods
 }
 
+func (r ListMergeKeyRule) AsMods() []ctlres.ResourceModWithMultiple {
+	var mods []ctlres.ResourceModWithMultiple
+	for _, matcher := range r.ResourceMatchers {
+		mods = append(mods, ctlres.ListMergeKeyMod{
+			ResourceMatcher: matcher.AsResourceMatcher(),
+			Path:            r.Path,
+			MergeKey:        r.MergeKey,
+		})
+	}
+	return mods
+}
+
+func (r DiffMaskRule) AsMods() []ctlres.FieldMaskMod {
+	var mods []ctlres.FieldMaskMod
+	for _, matcher := range r.ResourceMatchers {
+		mods = append(mods, ctlres.FieldMaskMod{
+			ResourceMatcher: matcher.AsResourceMatcher(),
+			Path:            r.Path,
+		})
+	}
+	return mods
+}
+
 func (r DiffAgainstLastAppliedFieldExclusionRule) AsMods() []ctlres.FieldRemoveMod {
 	var mods []ctlres.FieldRemoveMod
 	for _, matcher := range r.ResourceMatchers {
@@ -139,13 +139,13 @@ ownershipLabelRules:
   - apiVersionKindMatcher: {apiVersion: extensions/v1beta1, kind: StatefulSet}
 
 - path: [spec, template, metadata, labels]
-  resourceMatchers:
+  resourceMatchers: &builtinJobs
   - apiVersionKindMatcher: {apiVersion: batch/v1, kind: Job}
   - apiVersionKindMatcher: {apiVersion: batch/v1beta1, kind: Job}
   - apiVersionKindMatcher: {apiVersion: batch/v2alpha1, kind: Job}
 
 - path: [spec, jobTemplate, spec, template, metadata, labels]
-  resourceMatchers:
+  resourceMatchers: &builtinCronJobs
   - apiVersionKindMatcher: {apiVersion: batch/v1beta1, kind: CronJob}
   - apiVersionKindMatcher: {apiVersion: batch/v2alpha1, kind: CronJob}
 
@@ -161,6 +161,57 @@ labelScopingRules:
   resourceMatchers:
   - apiVersionKindMatcher: {apiVersion: policy/v1beta1, kind: PodDisruptionBudget}
 
+# Used only for structured diffing (--diff-structured); init containers,
+# env vars and volume mounts are not included since their order matters
+listMergeKeyRules:
+- path: [spec, containers]
+  mergeKey: name
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- path: [spec, volumes]
+  mergeKey: name
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- path: [spec, containers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Pod}
+- path: [spec, template, spec, containers]
+  mergeKey: name
+  resourceMatchers: *builtinAppsControllers
+- path: [spec, template, spec, volumes]
+  mergeKey: name
+  resourceMatchers: *builtinAppsControllers
+- path: [spec, template, spec, containers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers: *builtinAppsControllers
+- path: [spec, template, spec, containers]
+  mergeKey: name
+  resourceMatchers: *builtinJobs
+- path: [spec, template, spec, volumes]
+  mergeKey: name
+  resourceMatchers: *builtinJobs
+- path: [spec, template, spec, containers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers: *builtinJobs
+- path: [spec, jobTemplate, spec, template, spec, containers]
+  mergeKey: name
+  resourceMatchers: *builtinCronJobs
+- path: [spec, jobTemplate, spec, template, spec, volumes]
+  mergeKey: name
+  resourceMatchers: *builtinCronJobs
+- path: [spec, jobTemplate, spec, template, spec, containers, {allIndexes: true}, ports]
+  mergeKey: containerPort
+  resourceMatchers: *builtinCronJobs
+
+diffMaskRules:
+- path: [data]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+- path: [stringData]
+  resourceMatchers:
+  - apiVersionKindMatcher: {apiVersion: v1, kind: Secret}
+
 templateRules:
 - resourceMatchers:
   - apiVersionKindMatcher: {apiVersion: v1, kind: ConfigMap}
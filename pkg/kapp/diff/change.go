@@ -34,19 +34,36 @@ type Change interface {
 	IsIgnored() bool
 }
 
+// ChangeOpts configure how resources are compared and presented;
+// they do not affect resources that are applied
+type ChangeOpts struct {
+	// Structured compares resources field by field (each line of text diff
+	// is a field path) after aligning list items via ListMergeKeyMods
+	Structured       bool
+	ListMergeKeyMods []ctlres.ResourceModWithMultiple
+
+	// MaskMods hide sensitive values
+	MaskMods []ctlres.FieldMaskMod
+}
+
 type ChangeImpl struct {
 	existingRes, newRes ctlres.Resource
 
 	// appliedRes is an unmodified copy of what's being applied
 	appliedRes ctlres.Resource
 
+	// diffedExistingRes and diffedNewRes are copies of resources
+	// that are diffed (e.g. with list items aligned and sensitive values masked)
+	diffedExistingRes, diffedNewRes ctlres.Resource
+	structured                      bool
+
 	textDiff *TextDiff
 	opsDiff  *OpsDiff
 }
 
 var _ Change = &ChangeImpl{}
 
-func NewChange(existingRes, newRes, appliedRes ctlres.Resource) *ChangeImpl {
+func NewChange(existingRes, newRes, appliedRes ctlres.Resource, opts ChangeOpts) (*ChangeImpl, error) {
 	if existingRes == nil && newRes == nil {
 		panic("Expected either existingRes or newRes be non-nil")
 	}
@@ -61,7 +78,13 @@ func NewChange(existingRes, newRes, appliedRes ctlres.Resource) *ChangeImpl {
 		appliedRes = appliedRes.DeepCopy()
 	}
 
-	return &ChangeImpl{existingRes: existingRes, newRes: newRes, appliedRes: appliedRes}
+	diffedExistingRes, diffedNewRes, err := diffedResources(existingRes, newRes, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChangeImpl{existingRes: existingRes, newRes: newRes, appliedRes: appliedRes,
+		diffedExistingRes: diffedExistingRes, diffedNewRes: diffedNewRes, structured: opts.Structured}, nil
 }
 
 func (d *ChangeImpl) NewOrExistingResource() ctlres.Resource {
@@ -127,20 +150,12 @@ func (d *ChangeImpl) calculateTextDiff() TextDiff {
 	existingLines := []string{}
 	newLines := []string{}
 
-	if d.existingRes != nil {
-		existingBytes, err := d.existingRes.AsYAMLBytes()
-		if err != nil {
-			panic("yamling existingRes") // TODO panic
-		}
-		existingLines = strings.Split(string(existingBytes), "\n")
+	if d.diffedExistingRes != nil {
+		existingLines = d.resourceLines(d.diffedExistingRes)
 	}
 
-	if d.newRes != nil {
-		newBytes, err := d.newRes.AsYAMLBytes()
-		if err != nil {
-			panic("yamling newRes") // TODO panic
-		}
-		newLines = strings.Split(string(newBytes), "\n")
+	if d.diffedNewRes != nil {
+		newLines = d.resourceLines(d.diffedNewRes)
 	} else if d.IsIgnored() {
 		newLines = existingLines // show as no changes
 	}
@@ -148,12 +163,25 @@ func (d *ChangeImpl) calculateTextDiff() TextDiff {
 	return NewTextDiff(existingLines, newLines)
 }
 
+func (d *ChangeImpl) resourceLines(res ctlres.Resource) []string {
+	if d.structured {
+		return NewFieldPathLines(res).Lines()
+	}
+
+	bytes, err := res.AsYAMLBytes()
+	if err != nil {
+		panic("yamling res") // TODO panic
+	}
+
+	return strings.Split(string(bytes), "\n")
+}
+
 func (d *ChangeImpl) calculateOpsDiff() OpsDiff {
 	var existingObj interface{}
 	var newObj interface{}
 
-	if d.existingRes != nil {
-		existingBytes, err := d.existingRes.AsYAMLBytes()
+	if d.diffedExistingRes != nil {
+		existingBytes, err := d.diffedExistingRes.AsYAMLBytes()
 		if err != nil {
 			panic("yamling existingRes") // TODO panic
 		}
@@ -164,8 +192,8 @@ func (d *ChangeImpl) calculateOpsDiff() OpsDiff {
 		}
 	}
 
-	if d.newRes != nil {
-		newBytes, err := d.newRes.AsYAMLBytes()
+	if d.diffedNewRes != nil {
+		newBytes, err := d.diffedNewRes.AsYAMLBytes()
 		if err != nil {
 			panic("yamling newRes") // TODO panic
 		}
@@ -180,3 +208,51 @@ func (d *ChangeImpl) calculateOpsDiff() OpsDiff {
 
 	return OpsDiff(patch.Diff{Left: existingObj, Right: newObj}.Calculate())
 }
+
+// diffedResources returns copies of resources that are diffed. List items
+// of new resource are aligned with existing resource only for diffing,
+// hence resource that is applied keeps its list order.
+func diffedResources(existingRes, newRes ctlres.Resource,
+	opts ChangeOpts) (ctlres.Resource, ctlres.Resource, error) {
+
+	if opts.Structured && existingRes != nil && newRes != nil && len(opts.ListMergeKeyMods) > 0 {
+		newRes = newRes.DeepCopy()
+		srcs := map[ctlres.FieldCopyModSource]ctlres.Resource{ctlres.FieldCopyModSourceExisting: existingRes}
+
+		for _, t := range opts.ListMergeKeyMods {
+			err := t.ApplyFromMultiple(newRes, srcs)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	existingRes, err := maskResource(existingRes, opts.MaskMods)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newRes, err = maskResource(newRes, opts.MaskMods)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return existingRes, newRes, nil
+}
+
+func maskResource(res ctlres.Resource, maskMods []ctlres.FieldMaskMod) (ctlres.Resource, error) {
+	if res == nil || len(maskMods) == 0 {
+		return res, nil
+	}
+
+	res = res.DeepCopy()
+
+	for _, t := range maskMods {
+		err := t.Apply(res)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
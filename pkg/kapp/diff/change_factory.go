@@ -1,18 +1,24 @@
 package diff
 
 import (
+	"fmt"
+
+	"github.com/cppforlife/go-patch/patch"
 	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+	"gopkg.in/yaml.v2"
 )
 
 type ChangeFactory struct {
 	rebaseMods                               []ctlres.ResourceModWithMultiple
 	diffAgainstLastAppliedFieldExclusionMods []ctlres.FieldRemoveMod
+	changeOpts                               ChangeOpts
 }
 
 func NewChangeFactory(rebaseMods []ctlres.ResourceModWithMultiple,
-	diffAgainstLastAppliedFieldExclusionMods []ctlres.FieldRemoveMod) ChangeFactory {
+	diffAgainstLastAppliedFieldExclusionMods []ctlres.FieldRemoveMod,
+	changeOpts ChangeOpts) ChangeFactory {
 
-	return ChangeFactory{rebaseMods, diffAgainstLastAppliedFieldExclusionMods}
+	return ChangeFactory{rebaseMods, diffAgainstLastAppliedFieldExclusionMods, changeOpts}
 }
 
 func (f ChangeFactory) NewChangeAgainstLastApplied(existingRes, newRes ctlres.Resource) (Change, error) {
@@ -48,7 +54,167 @@ func (f ChangeFactory) NewChangeAgainstLastApplied(existingRes, newRes ctlres.Re
 		return nil, err
 	}
 
-	return NewChange(existingRes, rebasedNewRes, newRes), nil
+	return NewChange(existingRes, rebasedNewRes, newRes, f.changeOpts)
+}
+
+// NewChangeSinceLastApplied returns change made to existing resource outside
+// of kapp since it was last applied. Nil is returned if there were no such changes
+// or if resource was not applied by kapp.
+//
+// Change is calculated the same way as it was when last applied resource
+// was recorded (with excluded fields removed); only differences that
+// were not already present at that time (e.g. fields defaulted by the server)
+// are considered to be changes since last apply.
+func (f ChangeFactory) NewChangeSinceLastApplied(existingRes ctlres.Resource) (Change, error) {
+	resWithHistory := f.NewResourceWithHistory(existingRes)
+
+	if resWithHistory.LastAppliedResource() != nil {
+		return nil, nil // resource has not changed since last apply
+	}
+
+	lastAppliedRes := resWithHistory.RecordedLastAppliedResource()
+	if lastAppliedRes == nil {
+		return nil, nil
+	}
+
+	currChange, err := resWithHistory.CalculateChange(lastAppliedRes)
+	if err != nil {
+		return nil, err
+	}
+
+	driftedOps, err := f.opsSinceLastApplied(existingRes, currChange.OpsDiff())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(driftedOps) == 0 {
+		return nil, nil
+	}
+
+	existingRes = currChange.ExistingResource()
+
+	// Revert changes made since last apply to get expected state
+	// of the resource right after last apply
+	expectedRes, err := f.applyOps(existingRes, driftedOps)
+	if err != nil {
+		// Fallback to showing all differences from last applied resource
+		expectedRes, err = NewRebasedResource(existingRes, lastAppliedRes, f.rebaseMods).Resource()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingRes, err = f.historylessResourceWithoutEmptyAnns(existingRes)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedRes, err = f.historylessResourceWithoutEmptyAnns(expectedRes)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := NewChange(expectedRes, existingRes, existingRes, f.changeOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.Op() == ChangeOpKeep {
+		return nil, nil
+	}
+
+	return change, nil
+}
+
+// opsSinceLastApplied returns ops that were not part of recorded diff
+// between resource and last applied resource
+func (f ChangeFactory) opsSinceLastApplied(existingRes ctlres.Resource, opsDiff OpsDiff) ([]patch.OpDefinition, error) {
+	var recordedOpDefs []patch.OpDefinition
+
+	err := yaml.Unmarshal([]byte(existingRes.Annotations()[appliedResDiffAnnKey]), &recordedOpDefs)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling recorded diff: %s", err)
+	}
+
+	recordedOps := map[string]struct{}{}
+
+	for _, opDef := range recordedOpDefs {
+		opDefBytes, err := yaml.Marshal(opDef)
+		if err != nil {
+			return nil, err
+		}
+		recordedOps[string(opDefBytes)] = struct{}{}
+	}
+
+	opDefs, err := opsDiff.Definitions()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []patch.OpDefinition
+
+	for _, opDef := range opDefs {
+		opDefBytes, err := yaml.Marshal(opDef)
+		if err != nil {
+			return nil, err
+		}
+		if _, found := recordedOps[string(opDefBytes)]; !found {
+			result = append(result, opDef)
+		}
+	}
+
+	return result, nil
+}
+
+func (f ChangeFactory) applyOps(res ctlres.Resource, opDefs []patch.OpDefinition) (ctlres.Resource, error) {
+	ops, err := patch.NewOpsFromDefinitions(opDefs)
+	if err != nil {
+		return nil, err
+	}
+
+	resBytes, err := res.AsYAMLBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var resObj interface{}
+
+	err = yaml.Unmarshal(resBytes, &resObj)
+	if err != nil {
+		return nil, err
+	}
+
+	resObj, err = ops.Apply(resObj)
+	if err != nil {
+		return nil, err
+	}
+
+	resBytes, err = yaml.Marshal(resObj)
+	if err != nil {
+		return nil, err
+	}
+
+	return ctlres.NewResourceFromBytes(resBytes)
+}
+
+func (f ChangeFactory) historylessResourceWithoutEmptyAnns(res ctlres.Resource) (ctlres.Resource, error) {
+	historylessRes, err := f.NewResourceWithHistory(res).HistorylessResource()
+	if err != nil {
+		return nil, err
+	}
+
+	// Removal of history annotations may leave empty annotations behind
+	if len(historylessRes.Annotations()) == 0 {
+		err := ctlres.FieldRemoveMod{
+			ResourceMatcher: ctlres.AllResourceMatcher{},
+			Path:            ctlres.NewPathFromStrings([]string{"metadata", "annotations"}),
+		}.Apply(historylessRes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return historylessRes, nil
 }
 
 func (f ChangeFactory) NewExactChange(existingRes, newRes ctlres.Resource) (Change, error) {
@@ -75,7 +241,9 @@ func (f ChangeFactory) NewExactChange(existingRes, newRes ctlres.Resource) (Chan
 		return nil, err
 	}
 
-	return NewChange(existingRes, rebasedNewRes, newRes), nil
+	// Exact changes are used to record and verify last applied resource,
+	// hence they should not depend on how changes are presented
+	return NewChange(existingRes, rebasedNewRes, newRes, ChangeOpts{MaskMods: f.changeOpts.MaskMods})
 }
 
 func (f ChangeFactory) NewResourceWithHistory(resource ctlres.Resource) ResourceWithHistory {
@@ -0,0 +1,125 @@
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestChangeFactory_NewChangeSinceLastApplied(t *testing.T) {
+	appliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+data:
+  key: val
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, ctldiff.ChangeOpts{})
+
+	change, err := changeFactory.NewExactChange(appliedRes, appliedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	recordedRes, err := changeFactory.NewResourceWithHistory(appliedRes).RecordLastAppliedResource(change)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	driftChange, err := changeFactory.NewChangeSinceLastApplied(recordedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+	if driftChange != nil {
+		t.Fatalf("Expected no drift for unchanged resource, but was:\n%s", driftChange.TextDiff().FullString())
+	}
+
+	editedRes := recordedRes.DeepCopy()
+
+	err = ctlres.FieldRemoveMod{
+		ResourceMatcher: ctlres.AllResourceMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"data", "key"}),
+	}.Apply(editedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	driftChange, err = changeFactory.NewChangeSinceLastApplied(editedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+	if driftChange == nil || driftChange.Op() != ctldiff.ChangeOpUpdate {
+		t.Fatalf("Expected drift for edited resource")
+	}
+}
+
+func TestChangeFactory_NewChangeSinceLastAppliedIgnoresServerDefaults(t *testing.T) {
+	appliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Service
+metadata:
+  name: my-svc
+spec:
+  ports:
+  - port: 80
+`))
+
+	// Server defaults fields that are not part of applied resource
+	liveRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Service
+metadata:
+  name: my-svc
+spec:
+  clusterIP: 10.0.0.1
+  ports:
+  - port: 80
+    protocol: TCP
+`))
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, ctldiff.ChangeOpts{})
+
+	change, err := changeFactory.NewExactChange(liveRes, appliedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	recordedRes, err := changeFactory.NewResourceWithHistory(liveRes).RecordLastAppliedResource(change)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	driftChange, err := changeFactory.NewChangeSinceLastApplied(recordedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+	if driftChange != nil {
+		t.Fatalf("Expected no drift for server defaulted fields, but was:\n%s", driftChange.TextDiff().FullString())
+	}
+
+	editedRes := recordedRes.DeepCopy()
+
+	err = ctlres.StringMapAppendMod{
+		ResourceMatcher: ctlres.AllResourceMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"spec"}),
+		KVs:             map[string]string{"type": "NodePort"},
+	}.Apply(editedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	driftChange, err = changeFactory.NewChangeSinceLastApplied(editedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err: %s", err)
+	}
+	if driftChange == nil {
+		t.Fatalf("Expected drift for edited resource")
+	}
+
+	expectedDiff := `  8,  8 +   type: NodePort
+`
+	if driftChange.TextDiff().MinimalString() != expectedDiff {
+		t.Fatalf("Expected only edited field to be reported as drift, but was:\n%s",
+			driftChange.TextDiff().MinimalString())
+	}
+}
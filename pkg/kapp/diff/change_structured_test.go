@@ -0,0 +1,96 @@
+package diff_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestChangeStructured(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - name: a
+    image: a:1
+  - name: b
+    image: b:1
+`))
+
+	reorderedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - name: b
+    image: b:1
+  - name: a
+    image: a:1
+`))
+
+	reorderedAndChangedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Pod
+metadata:
+  name: my-pod
+spec:
+  containers:
+  - name: b
+    image: b:1
+  - name: a
+    image: a:2
+`))
+
+	opts := ctldiff.ChangeOpts{
+		Structured: true,
+		ListMergeKeyMods: []ctlres.ResourceModWithMultiple{
+			ctlres.ListMergeKeyMod{
+				ResourceMatcher: ctlres.AllResourceMatcher{},
+				Path:            ctlres.NewPathFromStrings([]string{"spec", "containers"}),
+				MergeKey:        "name",
+			},
+		},
+	}
+
+	changeFactory := ctldiff.NewChangeFactory(nil, nil, opts)
+
+	change, err := changeFactory.NewChangeAgainstLastApplied(existingRes, reorderedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err: %s", err)
+	}
+	if change.Op() != ctldiff.ChangeOpKeep {
+		t.Fatalf("Expected pure reorder to be a no-op, but was:\n%s", change.TextDiff().FullString())
+	}
+
+	change, err = changeFactory.NewChangeAgainstLastApplied(existingRes, reorderedAndChangedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err: %s", err)
+	}
+	if change.Op() != ctldiff.ChangeOpUpdate {
+		t.Fatalf("Expected change to be an update")
+	}
+
+	diffLines := change.TextDiff().MinimalString()
+	if !strings.Contains(diffLines, `- spec.containers[name=a].image: "a:1"`) ||
+		!strings.Contains(diffLines, `+ spec.containers[name=a].image: "a:2"`) {
+		t.Fatalf("Expected diff to be shown per field path, but was:\n%s", diffLines)
+	}
+	if strings.Contains(diffLines, "name=b") {
+		t.Fatalf("Expected reordered container to not be shown as changed, but was:\n%s", diffLines)
+	}
+
+	// Resource that is applied keeps list order as specified
+	containers := change.NewResource().DeepCopyRaw()["spec"].(map[string]interface{})["containers"].([]interface{})
+	var names []string
+	for _, container := range containers {
+		names = append(names, container.(map[string]interface{})["name"].(string))
+	}
+	if !reflect.DeepEqual(names, []string{"b", "a"}) {
+		t.Fatalf("Expected applied resource to keep container order, but was: %#v", names)
+	}
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+var (
+	fieldPathPlainKey = regexp.MustCompile("^[a-zA-Z0-9_-]+$")
+)
+
+// FieldPathLines represents resource as a list of lines, one per leaf field
+// (e.g. 'spec.template.spec.containers[name=app].image: "nginx"'), so that
+// each change is shown with its full field path. List items are identified
+// by their names when all items have unique names, otherwise by their indexes.
+type FieldPathLines struct {
+	res ctlres.Resource
+}
+
+func NewFieldPathLines(res ctlres.Resource) FieldPathLines {
+	return FieldPathLines{res}
+}
+
+func (l FieldPathLines) Lines() []string {
+	var lines []string
+	l.addLines(&lines, "", l.res.DeepCopyRaw())
+	return lines
+}
+
+func (l FieldPathLines) addLines(lines *[]string, path string, obj interface{}) {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		if len(typedObj) == 0 {
+			*lines = append(*lines, path+": {}")
+			return
+		}
+
+		var keys []string
+		for key := range typedObj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			l.addLines(lines, l.keyPath(path, key), typedObj[key])
+		}
+
+	case []interface{}:
+		if len(typedObj) == 0 {
+			*lines = append(*lines, path+": []")
+			return
+		}
+
+		names, named := l.itemNames(typedObj)
+
+		for i, item := range typedObj {
+			if named {
+				l.addLines(lines, fmt.Sprintf("%s[name=%s]", path, names[i]), item)
+			} else {
+				l.addLines(lines, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+
+	default:
+		valBytes, err := json.Marshal(typedObj)
+		if err != nil {
+			valBytes = []byte(fmt.Sprintf("%v", typedObj))
+		}
+		*lines = append(*lines, path+": "+string(valBytes))
+	}
+}
+
+func (FieldPathLines) keyPath(path, key string) string {
+	if !fieldPathPlainKey.MatchString(key) {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	if len(path) == 0 {
+		return key
+	}
+	return path + "." + key
+}
+
+func (FieldPathLines) itemNames(list []interface{}) ([]string, bool) {
+	var names []string
+	seen := map[string]struct{}{}
+
+	for _, item := range list {
+		typedItem, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		name, ok := typedItem["name"].(string)
+		if !ok {
+			return nil, false
+		}
+		if _, found := seen[name]; found {
+			return nil, false
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+
+	return names, true
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+// NewMergePatch returns JSON merge patch (RFC 7386) that turns existing
+// resource into new resource. Lists are replaced entirely (merge patches
+// cannot address list items). Fields not present in new resource are only
+// removed if they were present in last applied resource (which may be nil),
+// so that fields set by the server or other controllers are left as is.
+func NewMergePatch(existingRes, newRes, lastAppliedRes ctlres.Resource) ([]byte, error) {
+	var lastApplied map[string]interface{}
+	if lastAppliedRes != nil {
+		lastApplied = lastAppliedRes.DeepCopyRaw()
+	}
+
+	patch := mergePatch(existingRes.DeepCopyRaw(), newRes.DeepCopyRaw(), lastApplied)
+
+	return json.Marshal(patch)
+}
+
+func mergePatch(existing, new, lastApplied map[string]interface{}) map[string]interface{} {
+	patch := map[string]interface{}{}
+
+	for key, existingVal := range existing {
+		if _, found := new[key]; found {
+			continue
+		}
+
+		lastAppliedVal, applied := lastApplied[key]
+		if !applied {
+			continue
+		}
+
+		existingValMap, existingIsMap := existingVal.(map[string]interface{})
+		lastAppliedValMap, lastAppliedIsMap := lastAppliedVal.(map[string]interface{})
+
+		if existingIsMap && lastAppliedIsMap {
+			// Only remove previously applied keys since map
+			// may include keys set by the server (e.g. annotations)
+			subPatch := mergePatch(existingValMap, map[string]interface{}{}, lastAppliedValMap)
+			if len(subPatch) > 0 {
+				patch[key] = subPatch
+			}
+			continue
+		}
+
+		patch[key] = nil // null removes key in merge patch
+	}
+
+	for key, newVal := range new {
+		existingVal, found := existing[key]
+		if !found {
+			patch[key] = newVal
+			continue
+		}
+
+		existingValMap, existingIsMap := existingVal.(map[string]interface{})
+		newValMap, newIsMap := newVal.(map[string]interface{})
+
+		if existingIsMap && newIsMap {
+			lastAppliedValMap, _ := lastApplied[key].(map[string]interface{})
+
+			subPatch := mergePatch(existingValMap, newValMap, lastAppliedValMap)
+			if len(subPatch) > 0 {
+				patch[key] = subPatch
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(existingVal, newVal) {
+			patch[key] = newVal
+		}
+	}
+
+	return patch
+}
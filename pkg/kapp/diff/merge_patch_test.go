@@ -0,0 +1,115 @@
+package diff_test
+
+import (
+	"testing"
+
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestNewMergePatch(t *testing.T) {
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+  labels:
+    keep: val
+    removed: val
+data:
+  changed: val1
+  unchanged: val
+list:
+- a
+- b
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: ConfigMap
+metadata:
+  name: my-res
+  labels:
+    keep: val
+data:
+  added: val
+  changed: val2
+  unchanged: val
+list:
+- a
+`))
+
+	patch, err := ctldiff.NewMergePatch(existingRes, newRes, existingRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	expectedPatch := `{"data":{"added":"val","changed":"val2"},"list":["a"],"metadata":{"labels":{"removed":null}}}`
+
+	if string(patch) != expectedPatch {
+		t.Fatalf("Expected patch to match:\n%s\nvs\n%s", patch, expectedPatch)
+	}
+
+	patch, err = ctldiff.NewMergePatch(existingRes, existingRes, existingRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	if string(patch) != "{}" {
+		t.Fatalf("Expected empty patch for same resources, but was: %s", patch)
+	}
+}
+
+func TestNewMergePatchKeepsFieldsNotLastApplied(t *testing.T) {
+	// Live resource includes fields set by the server and other controllers
+	existingRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Deployment
+metadata:
+  name: my-res
+  annotations:
+    deployment.kubernetes.io/revision: "2"
+    removed: val
+spec:
+  replicas: 5
+  paused: false
+`))
+
+	newRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Deployment
+metadata:
+  name: my-res
+spec:
+  paused: true
+`))
+
+	lastAppliedRes := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Deployment
+metadata:
+  name: my-res
+  annotations:
+    removed: val
+spec:
+  paused: false
+`))
+
+	patch, err := ctldiff.NewMergePatch(existingRes, newRes, lastAppliedRes)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	expectedPatch := `{"metadata":{"annotations":{"removed":null}},"spec":{"paused":true}}`
+
+	if string(patch) != expectedPatch {
+		t.Fatalf("Expected patch to match:\n%s\nvs\n%s", patch, expectedPatch)
+	}
+
+	// Without last applied resource only added and changed fields are patched
+	patch, err = ctldiff.NewMergePatch(existingRes, newRes, nil)
+	if err != nil {
+		t.Fatalf("Expected non-err")
+	}
+
+	expectedPatch = `{"spec":{"paused":true}}`
+
+	if string(patch) != expectedPatch {
+		t.Fatalf("Expected patch to match:\n%s\nvs\n%s", patch, expectedPatch)
+	}
+}
@@ -18,8 +18,12 @@ func (l OpsDiff) MinimalMD5() string {
 
 func (l OpsDiff) FullString() string { return "" }
 
+func (l OpsDiff) Definitions() ([]patch.OpDefinition, error) {
+	return patch.NewOpDefinitionsFromOps(patch.Ops(l))
+}
+
 func (l OpsDiff) MinimalString() string {
-	opsDefs, err := patch.NewOpDefinitionsFromOps(patch.Ops(l))
+	opsDefs, err := l.Definitions()
 	if err != nil {
 		panic("building opdefs") // TODO panic
 	}
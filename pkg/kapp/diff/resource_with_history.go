@@ -55,11 +55,34 @@ func (r ResourceWithHistory) LastAppliedResource() ctlres.Resource {
 	return nil
 }
 
+// RecordedLastAppliedResource returns last applied resource as it was recorded
+// without checking whether resource was changed since then
+func (r ResourceWithHistory) RecordedLastAppliedResource() ctlres.Resource {
+	lastAppliedResBytes := r.resource.Annotations()[appliedResAnnKey]
+	if len(lastAppliedResBytes) == 0 {
+		return nil
+	}
+
+	lastAppliedRes, err := ctlres.NewResourceFromBytes([]byte(lastAppliedResBytes))
+	if err != nil {
+		return nil
+	}
+
+	return lastAppliedRes
+}
+
 func (r ResourceWithHistory) RecordLastAppliedResource(appliedChange Change) (ctlres.Resource, error) {
 	// Use compact representation to take as little space as possible
 	// because annotation value max length is 262144 characters
 	// (https://github.com/k14s/kapp/issues/48).
-	appliedResBytes, err := appliedChange.AppliedResource().AsCompactBytes()
+	// Sensitive values are recorded only as hashes; since diffs
+	// are calculated against masked values, it's sufficient for diffing.
+	appliedRes, err := maskResource(appliedChange.AppliedResource(), r.changeFactory.changeOpts.MaskMods)
+	if err != nil {
+		return nil, err
+	}
+
+	appliedResBytes, err := appliedRes.AsCompactBytes()
 	if err != nil {
 		return nil, err
 	}
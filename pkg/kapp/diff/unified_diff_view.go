@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aryann/difflib"
+)
+
+// UnifiedDiffView renders text diff in a format understood by patch tools
+type UnifiedDiffView struct {
+	diff TextDiff
+	opts TextDiffViewOpts
+}
+
+func NewUnifiedDiffView(diff TextDiff, opts TextDiffViewOpts) UnifiedDiffView {
+	return UnifiedDiffView{diff, opts}
+}
+
+func (v UnifiedDiffView) String(fromName, toName string) string {
+	records := v.diff
+
+	// YAML ends with a new line which results in a trailing empty line
+	if len(records) > 0 && records[len(records)-1].Payload == "" {
+		records = records[:len(records)-1]
+	}
+
+	// Positions (0-based) of each record in left and right sides
+	leftPos := make([]int, len(records)+1)
+	rightPos := make([]int, len(records)+1)
+
+	for i, record := range records {
+		leftPos[i+1] = leftPos[i]
+		rightPos[i+1] = rightPos[i]
+
+		switch record.Delta {
+		case difflib.Common:
+			leftPos[i+1]++
+			rightPos[i+1]++
+		case difflib.LeftOnly:
+			leftPos[i+1]++
+		case difflib.RightOnly:
+			rightPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+
+	for _, hunk := range v.hunks(records) {
+		start, end := hunk[0], hunk[1]
+
+		sb.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			v.hunkRange(leftPos[start], leftPos[end]), v.hunkRange(rightPos[start], rightPos[end])))
+
+		for _, record := range records[start:end] {
+			switch record.Delta {
+			case difflib.Common:
+				sb.WriteString(" " + record.Payload + "\n")
+			case difflib.LeftOnly:
+				sb.WriteString("-" + record.Payload + "\n")
+			case difflib.RightOnly:
+				sb.WriteString("+" + record.Payload + "\n")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// hunks returns [start, end) record ranges that include changed lines with their context
+func (v UnifiedDiffView) hunks(records []difflib.DiffRecord) [][2]int {
+	var result [][2]int
+
+	for i, record := range records {
+		if record.Delta == difflib.Common {
+			continue
+		}
+
+		start, end := 0, len(records)
+		if v.opts.Context >= 0 {
+			start, end = i-v.opts.Context, i+v.opts.Context+1
+			if start < 0 {
+				start = 0
+			}
+			if end > len(records) {
+				end = len(records)
+			}
+		}
+
+		if len(result) > 0 && start <= result[len(result)-1][1] {
+			result[len(result)-1][1] = end
+		} else {
+			result = append(result, [2]int{start, end})
+		}
+	}
+
+	return result
+}
+
+func (v UnifiedDiffView) hunkRange(start, end int) string {
+	count := end - start
+	if count == 0 {
+		// Empty ranges refer to the line preceding the hunk
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
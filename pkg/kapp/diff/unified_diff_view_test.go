@@ -0,0 +1,54 @@
+package diff_test
+
+import (
+	"strings"
+	"testing"
+
+	ctldiff "github.com/k14s/kapp/pkg/kapp/diff"
+)
+
+func TestUnifiedDiffView(t *testing.T) {
+	existingLines := strings.Split("a\nb\nc\nd\ne\nf\ng\nh\n", "\n")
+	newLines := strings.Split("a\nb\nc2\nd\ne\nf\ng\nh\ni\n", "\n")
+
+	textDiff := ctldiff.NewTextDiff(existingLines, newLines)
+	opts := ctldiff.TextDiffViewOpts{Context: 1}
+
+	result := ctldiff.NewUnifiedDiffView(textDiff, opts).String("res", "res")
+
+	expected := strings.TrimPrefix(`
+--- res
++++ res
+@@ -2,3 +2,3 @@
+ b
+-c
++c2
+ d
+@@ -8,1 +8,2 @@
+ h
++i
+`, "\n")
+
+	if result != expected {
+		t.Fatalf("Expected unified diff to match, but was:\n%s", result)
+	}
+}
+
+func TestUnifiedDiffViewAddedResource(t *testing.T) {
+	textDiff := ctldiff.NewTextDiff([]string{}, strings.Split("a\nb\n", "\n"))
+	opts := ctldiff.TextDiffViewOpts{Context: 2}
+
+	result := ctldiff.NewUnifiedDiffView(textDiff, opts).String("/dev/null", "res")
+
+	expected := strings.TrimPrefix(`
+--- /dev/null
++++ res
+@@ -0,0 +1,2 @@
++a
++b
+`, "\n")
+
+	if result != expected {
+		t.Fatalf("Expected unified diff to match, but was:\n%s", result)
+	}
+}
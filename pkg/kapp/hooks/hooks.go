@@ -0,0 +1,156 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+type Phase string
+
+const (
+	AnnKey             = "kapp.k14s.io/hook"
+	DeletePolicyAnnKey = "kapp.k14s.io/hook-delete-policy"
+
+	PhasePreDeploy  Phase = "pre-deploy"
+	PhasePostDeploy Phase = "post-deploy"
+	PhasePreDelete  Phase = "pre-delete"
+	PhasePostDelete Phase = "post-delete"
+
+	DeletePolicyBeforeCreation = "before-hook-creation" // default
+	DeletePolicySucceeded      = "hook-succeeded"
+	DeletePolicyFailed         = "hook-failed"
+)
+
+var (
+	Phases = []Phase{PhasePreDeploy, PhasePostDeploy, PhasePreDelete, PhasePostDelete}
+)
+
+// Hooks are resources that are created (and waited on) in a particular
+// phase of each deploy or delete instead of being part of app resources
+type Hooks struct {
+	resources []ctlres.Resource
+}
+
+// NewHooks separates hook resources from regular resources
+func NewHooks(resources []ctlres.Resource) (Hooks, []ctlres.Resource, error) {
+	var hookRs, otherRs []ctlres.Resource
+
+	for _, res := range resources {
+		phase, found := res.Annotations()[AnnKey]
+		if !found {
+			otherRs = append(otherRs, res)
+			continue
+		}
+
+		var knownPhase bool
+		for _, hp := range Phases {
+			if Phase(phase) == hp {
+				knownPhase = true
+			}
+		}
+		if !knownPhase {
+			return Hooks{}, nil, fmt.Errorf("Expected annotation '%s' on resource '%s' to be one of: %s",
+				AnnKey, res.Description(), phasesStr())
+		}
+
+		for _, policy := range DeletePolicies(res) {
+			switch policy {
+			case DeletePolicyBeforeCreation, DeletePolicySucceeded, DeletePolicyFailed:
+			default:
+				return Hooks{}, nil, fmt.Errorf("Unknown hook delete policy '%s' on resource '%s'",
+					policy, res.Description())
+			}
+		}
+
+		hookRs = append(hookRs, res)
+	}
+
+	return Hooks{hookRs}, otherRs, nil
+}
+
+func (h Hooks) Resources() []ctlres.Resource { return h.resources }
+
+func (h Hooks) ForPhase(phase Phase) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range h.resources {
+		if Phase(res.Annotations()[AnnKey]) == phase {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// StaleInstances returns previously created hooks that
+// are no longer part of hooks (e.g. removed from configuration)
+func (h Hooks) StaleInstances(resources []ctlres.Resource) []ctlres.Resource {
+	hookKeys := map[string]struct{}{}
+
+	for _, res := range h.resources {
+		hookKeys[ctlres.NewUniqueResourceKey(res).String()] = struct{}{}
+	}
+
+	var result []ctlres.Resource
+
+	for _, res := range Instances(resources) {
+		if _, found := hookKeys[ctlres.NewUniqueResourceKey(res).String()]; !found {
+			result = append(result, res)
+		}
+	}
+
+	return result
+}
+
+// Instances returns previously created hooks
+func Instances(resources []ctlres.Resource) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if _, found := res.Annotations()[AnnKey]; found {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+// WithoutInstances removes previously created hooks
+// so that they do not get diffed against app resources
+func WithoutInstances(resources []ctlres.Resource) []ctlres.Resource {
+	var result []ctlres.Resource
+	for _, res := range resources {
+		if _, found := res.Annotations()[AnnKey]; !found {
+			result = append(result, res)
+		}
+	}
+	return result
+}
+
+func DeletePolicies(res ctlres.Resource) []string {
+	val, found := res.Annotations()[DeletePolicyAnnKey]
+	if !found {
+		return []string{DeletePolicyBeforeCreation}
+	}
+
+	var result []string
+	for _, policy := range strings.Split(val, ",") {
+		result = append(result, strings.TrimSpace(policy))
+	}
+	return result
+}
+
+func HasDeletePolicy(res ctlres.Resource, policy string) bool {
+	for _, p := range DeletePolicies(res) {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+func phasesStr() string {
+	var result []string
+	for _, phase := range Phases {
+		result = append(result, string(phase))
+	}
+	return strings.Join(result, ", ")
+}
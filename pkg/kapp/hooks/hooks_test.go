@@ -0,0 +1,129 @@
+package hooks_test
+
+import (
+	"reflect"
+	"testing"
+
+	ctlhooks "github.com/k14s/kapp/pkg/kapp/hooks"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestNewHooks(t *testing.T) {
+	resources := []ctlres.Resource{
+		newResource("app", ""),
+		newResource("pre", `kapp.k14s.io/hook: pre-deploy`),
+		newResource("post", `kapp.k14s.io/hook: post-deploy
+    kapp.k14s.io/hook-delete-policy: "hook-succeeded, hook-failed"`),
+	}
+
+	hooks, otherRs, err := ctlhooks.NewHooks(resources)
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	if len(otherRs) != 1 || otherRs[0].Name() != "app" {
+		t.Fatalf("Expected only non-hook resources to be returned, but was %#v", otherRs)
+	}
+	if len(hooks.Resources()) != 2 {
+		t.Fatalf("Expected two hooks, but was %d", len(hooks.Resources()))
+	}
+
+	preRs := hooks.ForPhase(ctlhooks.PhasePreDeploy)
+	if len(preRs) != 1 || preRs[0].Name() != "pre" {
+		t.Fatalf("Expected pre-deploy hook to be found, but was %#v", preRs)
+	}
+	if len(hooks.ForPhase(ctlhooks.PhasePreDelete)) != 0 {
+		t.Fatalf("Expected no pre-delete hooks")
+	}
+}
+
+func TestNewHooksInvalid(t *testing.T) {
+	exs := []struct {
+		Res         ctlres.Resource
+		ExpectedErr string
+	}{
+		{
+			newResource("hook", `kapp.k14s.io/hook: pre-create`),
+			"Expected annotation 'kapp.k14s.io/hook' on resource 'configmap/hook (v1) cluster' " +
+				"to be one of: pre-deploy, post-deploy, pre-delete, post-delete",
+		},
+		{
+			newResource("hook", `kapp.k14s.io/hook: pre-deploy
+    kapp.k14s.io/hook-delete-policy: hook-succeeded,never`),
+			"Unknown hook delete policy 'never' on resource 'configmap/hook (v1) cluster'",
+		},
+	}
+
+	for _, ex := range exs {
+		_, _, err := ctlhooks.NewHooks([]ctlres.Resource{ex.Res})
+		if err == nil {
+			t.Fatalf("Expected err for %s", ex.Res.Description())
+		}
+		if err.Error() != ex.ExpectedErr {
+			t.Fatalf("Expected err to be '%s', but was '%s'", ex.ExpectedErr, err)
+		}
+	}
+}
+
+func TestDeletePolicies(t *testing.T) {
+	exs := []struct {
+		Anns     string
+		Expected []string
+	}{
+		{`kapp.k14s.io/hook: pre-deploy`, []string{"before-hook-creation"}},
+		{`kapp.k14s.io/hook-delete-policy: hook-succeeded`, []string{"hook-succeeded"}},
+		{`kapp.k14s.io/hook-delete-policy: "hook-succeeded, hook-failed"`, []string{"hook-succeeded", "hook-failed"}},
+	}
+
+	for _, ex := range exs {
+		res := newResource("hook", ex.Anns)
+
+		policies := ctlhooks.DeletePolicies(res)
+		if !reflect.DeepEqual(policies, ex.Expected) {
+			t.Fatalf("Expected policies for '%s' to be %#v, but was %#v", ex.Anns, ex.Expected, policies)
+		}
+
+		for _, policy := range ex.Expected {
+			if !ctlhooks.HasDeletePolicy(res, policy) {
+				t.Fatalf("Expected '%s' to have policy '%s'", ex.Anns, policy)
+			}
+		}
+	}
+}
+
+func TestHooksStaleInstances(t *testing.T) {
+	hooks, _, err := ctlhooks.NewHooks([]ctlres.Resource{
+		newResource("current", `kapp.k14s.io/hook: pre-deploy`),
+	})
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	existingRs := []ctlres.Resource{
+		newResource("app", ""),
+		newResource("current", `kapp.k14s.io/hook: pre-deploy`),
+		newResource("removed", `kapp.k14s.io/hook: post-deploy`),
+	}
+
+	staleRs := hooks.StaleInstances(existingRs)
+	if len(staleRs) != 1 || staleRs[0].Name() != "removed" {
+		t.Fatalf("Expected only removed hook to be stale, but was %#v", staleRs)
+	}
+
+	otherRs := ctlhooks.WithoutInstances(existingRs)
+	if len(otherRs) != 1 || otherRs[0].Name() != "app" {
+		t.Fatalf("Expected only non-hook resources, but was %#v", otherRs)
+	}
+}
+
+func newResource(name, anns string) ctlres.Resource {
+	if len(anns) > 0 {
+		anns = "\n  annotations:\n    " + anns
+	}
+	return ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + anns + `
+`))
+}
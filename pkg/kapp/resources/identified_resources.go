@@ -73,12 +73,46 @@ func (r IdentifiedResources) Update(resource Resource) (Resource, error) {
 
 func (r IdentifiedResources) Patch(resource Resource, patchType types.PatchType, data []byte) (Resource, error) {
 	defer r.logger.DebugFunc(fmt.Sprintf("Patch(%s)", resource.Description())).Finish()
-	return r.resources.Patch(resource, patchType, data)
+
+	resource, err := r.resources.Patch(resource, patchType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (r IdentifiedResources) ServerSideApply(resource Resource, fieldManager string) (Resource, error) {
+	defer r.logger.DebugFunc(fmt.Sprintf("ServerSideApply(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err = r.resources.ServerSideApply(resource, fieldManager)
+	if err != nil {
+		return nil, err
+	}
+
+	err = NewIdentityAnnotation(resource).RemoveMod().Apply(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
 }
 
-func (r IdentifiedResources) Delete(resource Resource) error {
+func (r IdentifiedResources) Delete(resource Resource, opts DeleteOpts) error {
 	defer r.logger.DebugFunc(fmt.Sprintf("Delete(%s)", resource.Description())).Finish()
-	return r.resources.Delete(resource)
+	return r.resources.Delete(resource, opts)
 }
 
 func (r IdentifiedResources) Get(resource Resource) (Resource, error) {
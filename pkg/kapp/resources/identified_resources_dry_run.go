@@ -0,0 +1,56 @@
+package resources
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func (r IdentifiedResources) DryRunCreate(resource Resource) error {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRunCreate(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return err
+	}
+
+	return r.resources.DryRunCreate(resource)
+}
+
+func (r IdentifiedResources) DryRunUpdate(resource Resource) error {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRunUpdate(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return err
+	}
+
+	return r.resources.DryRunUpdate(resource)
+}
+
+func (r IdentifiedResources) DryRunDelete(resource Resource, opts DeleteOpts) error {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRunDelete(%s)", resource.Description())).Finish()
+	return r.resources.DryRunDelete(resource, opts)
+}
+
+func (r IdentifiedResources) DryRunPatch(resource Resource, patchType types.PatchType, data []byte) error {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRunPatch(%s)", resource.Description())).Finish()
+	return r.resources.DryRunPatch(resource, patchType, data)
+}
+
+func (r IdentifiedResources) DryRunServerSideApply(resource Resource, fieldManager string) error {
+	defer r.logger.DebugFunc(fmt.Sprintf("DryRunServerSideApply(%s)", resource.Description())).Finish()
+
+	resource = resource.DeepCopy()
+
+	err := NewIdentityAnnotation(resource).AddMod().Apply(resource)
+	if err != nil {
+		return err
+	}
+
+	return r.resources.DryRunServerSideApply(resource, fieldManager)
+}
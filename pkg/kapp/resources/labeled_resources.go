@@ -115,7 +115,7 @@ func (a *LabeledResources) AllAndMatching(newResources []Resource, opts AllAndMa
 	}
 
 	if !opts.SkipResourceOwnershipCheck && len(nonLabeledResources) > 0 {
-		err := a.checkResourceOwnership(nonLabeledResources)
+		err := a.CheckResourceOwnership(nonLabeledResources)
 		if err != nil {
 			return nil, err
 		}
@@ -131,7 +131,9 @@ func (a *LabeledResources) AllAndMatching(newResources []Resource, opts AllAndMa
 	return resources, nil
 }
 
-func (a *LabeledResources) checkResourceOwnership(resources []Resource) error {
+// CheckResourceOwnership returns error if any of resources
+// is associated with a different app (label value)
+func (a *LabeledResources) CheckResourceOwnership(resources []Resource) error {
 	expectedLabelKey, expectedLabelVal, err := NewSimpleLabel(a.labelSelector).KV()
 	if err != nil {
 		return err
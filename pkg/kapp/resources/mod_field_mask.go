@@ -0,0 +1,169 @@
+package resources
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+const (
+	fieldMaskPrefix = "(masked sha256:"
+	fieldMaskSuffix = ")"
+)
+
+// FieldMaskMod replaces sensitive values with their hashes so that
+// they could be compared (e.g. during diffing) without being revealed.
+// If path points to a map or an array, each of its values is masked.
+type FieldMaskMod struct {
+	ResourceMatcher ResourceMatcher
+	Path            Path
+}
+
+var _ ResourceMod = FieldMaskMod{}
+
+func (t FieldMaskMod) Apply(res Resource) error {
+	if !t.ResourceMatcher.Matches(res) {
+		return nil
+	}
+	err := t.apply(res.unstructured().Object, t.Path)
+	if err != nil {
+		return fmt.Errorf("FieldMaskMod for path '%s' on resource '%s': %s", t.Path.AsString(), res.Description(), err)
+	}
+	return nil
+}
+
+func (t FieldMaskMod) apply(obj interface{}, path Path) error {
+	for i, part := range path {
+		isLast := len(path) == i+1
+
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				if typedObj == nil {
+					return nil // map is a nil, nothing to mask
+				}
+				return fmt.Errorf("Unexpected non-map found: %T", obj)
+			}
+
+			val, found := typedObj[*part.MapKey]
+			if !found {
+				return nil // map key is not found, nothing to mask
+			}
+
+			if isLast {
+				typedObj[*part.MapKey] = t.mask(val)
+				return nil
+			}
+
+			obj = val
+
+		case part.ArrayIndex != nil:
+			if isLast {
+				return fmt.Errorf("Expected last part of the path to be map key")
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				typedObj, ok := obj.([]interface{})
+				if !ok {
+					return fmt.Errorf("Unexpected non-array found: %T", obj)
+				}
+
+				for _, obj := range typedObj {
+					err := t.apply(obj, path[i+1:])
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil // dealt with children, get out
+
+			case part.ArrayIndex.Index != nil:
+				typedObj, ok := obj.([]interface{})
+				if !ok {
+					return fmt.Errorf("Unexpected non-array found: %T", obj)
+				}
+
+				if *part.ArrayIndex.Index < len(typedObj) {
+					obj = typedObj[*part.ArrayIndex.Index]
+				} else {
+					return nil // index not found, nothing to mask
+				}
+
+			default:
+				panic(fmt.Sprintf("Unknown array index: %#v", part.ArrayIndex))
+			}
+
+		default:
+			panic(fmt.Sprintf("Unexpected path part: %#v", part))
+		}
+	}
+
+	panic("unreachable")
+}
+
+func (t FieldMaskMod) mask(val interface{}) interface{} {
+	switch typedVal := val.(type) {
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, v := range typedVal {
+			result[k] = t.maskValue(v)
+		}
+		return result
+
+	case []interface{}:
+		var result []interface{}
+		for _, v := range typedVal {
+			result = append(result, t.maskValue(v))
+		}
+		return result
+
+	default:
+		return t.maskValue(val)
+	}
+}
+
+func (FieldMaskMod) maskValue(val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	str := fmt.Sprintf("%v", val)
+
+	// Already masked values (e.g. recorded in last applied resource) are kept as is
+	if strings.HasPrefix(str, fieldMaskPrefix) && strings.HasSuffix(str, fieldMaskSuffix) {
+		return str
+	}
+
+	sum := sha256.Sum256([]byte(str))
+
+	return fmt.Sprintf("%s%x%s", fieldMaskPrefix, sum[:8], fieldMaskSuffix)
+}
+
+// HasMaskedValues indicates that resource includes masked values
+// (e.g. it was recorded with sensitive values masked), hence it
+// should not be applied as its original values are not known
+func HasMaskedValues(res Resource) bool {
+	return hasMaskedValues(res.unstructured().Object)
+}
+
+func hasMaskedValues(obj interface{}) bool {
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		for _, v := range typedObj {
+			if hasMaskedValues(v) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, v := range typedObj {
+			if hasMaskedValues(v) {
+				return true
+			}
+		}
+	case string:
+		return strings.HasPrefix(typedObj, fieldMaskPrefix) && strings.HasSuffix(typedObj, fieldMaskSuffix)
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package resources_test
+
+import (
+	"strings"
+	"testing"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestModFieldMask(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Secret
+data:
+  password: c2VjcmV0
+  username: YWRtaW4=
+type: Opaque`))
+
+	mod := ctlres.FieldMaskMod{
+		ResourceMatcher: ctlres.AllResourceMatcher{},
+		Path:            ctlres.NewPathFromStrings([]string{"data"}),
+	}
+
+	err := mod.Apply(res)
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	data := res.DeepCopyRaw()["data"].(map[string]interface{})
+
+	for _, key := range []string{"password", "username"} {
+		val := data[key].(string)
+		if !strings.HasPrefix(val, "(masked sha256:") {
+			t.Fatalf("Expected value for '%s' to be masked, but was '%s'", key, val)
+		}
+	}
+
+	if data["password"] == data["username"] {
+		t.Fatalf("Expected different values to have different masks")
+	}
+
+	if res.DeepCopyRaw()["type"] != "Opaque" {
+		t.Fatalf("Expected unrelated fields to be left as is")
+	}
+
+	maskedBs, err := res.AsYAMLBytes()
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	// Masking already masked values should not change them
+	err = mod.Apply(res)
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	remaskedBs, err := res.AsYAMLBytes()
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	expectEqualsStripped(t, "masking is idempotent", string(remaskedBs), string(maskedBs))
+
+	if !ctlres.HasMaskedValues(res) {
+		t.Fatalf("Expected resource to have masked values")
+	}
+}
+
+func TestHasMaskedValues(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+kind: Secret
+stringData:
+  password: "masked sha256:abc"
+  list:
+  - secret
+type: Opaque`))
+
+	if ctlres.HasMaskedValues(res) {
+		t.Fatalf("Expected resource to not have masked values")
+	}
+
+	res = ctlres.MustNewResourceFromBytes([]byte(`
+kind: Secret
+stringData:
+  list:
+  - "(masked sha256:0123456789abcdef)"
+type: Opaque`))
+
+	if !ctlres.HasMaskedValues(res) {
+		t.Fatalf("Expected resource to have masked values")
+	}
+}
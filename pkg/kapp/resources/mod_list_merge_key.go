@@ -0,0 +1,209 @@
+package resources
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ListMergeKeyMod reorders list items to follow order of matching items
+// (ones with the same merge key value) in existing resource, so that
+// lists that only differ in order of their items are treated as equal
+type ListMergeKeyMod struct {
+	ResourceMatcher ResourceMatcher
+	Path            Path // last part is expected to point to a list
+	MergeKey        string
+}
+
+var _ ResourceModWithMultiple = ListMergeKeyMod{}
+
+func (t ListMergeKeyMod) ApplyFromMultiple(res Resource, srcs map[FieldCopyModSource]Resource) error {
+	if res == nil || !t.ResourceMatcher.Matches(res) {
+		return nil
+	}
+
+	existingRes, found := srcs[FieldCopyModSourceExisting]
+	if !found || existingRes == nil {
+		return nil
+	}
+
+	err := t.apply(res.unstructured().Object, existingRes.unstructured().Object, t.Path)
+	if err != nil {
+		return fmt.Errorf("ListMergeKeyMod for path '%s' on resource '%s': %s", t.Path.AsString(), res.Description(), err)
+	}
+
+	return nil
+}
+
+func (t ListMergeKeyMod) apply(obj, existingObj interface{}, path Path) error {
+	for i, part := range path {
+		isLast := len(path) == i+1
+
+		switch {
+		case part.MapKey != nil:
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("Unexpected non-map found: %T", obj)
+			}
+
+			typedExistingObj, ok := existingObj.(map[string]interface{})
+			if !ok {
+				return nil // nothing to align with
+			}
+
+			if isLast {
+				t.alignList(typedObj, typedExistingObj, *part.MapKey)
+				return nil
+			}
+
+			obj = typedObj[*part.MapKey]
+			existingObj = typedExistingObj[*part.MapKey]
+
+			if obj == nil || existingObj == nil {
+				return nil
+			}
+
+		case part.ArrayIndex != nil:
+			if isLast {
+				panic("Expected last part of the path to be map key")
+			}
+
+			typedObj, ok := obj.([]interface{})
+			if !ok {
+				return fmt.Errorf("Unexpected non-array found: %T", obj)
+			}
+
+			typedExistingObj, ok := existingObj.([]interface{})
+			if !ok {
+				return nil // nothing to align with
+			}
+
+			switch {
+			case part.ArrayIndex.All != nil:
+				for objI, obj := range typedObj {
+					existingObj := t.correspondingItem(typedExistingObj, obj, objI)
+					if existingObj == nil {
+						continue
+					}
+
+					err := t.apply(obj, existingObj, path[i+1:])
+					if err != nil {
+						return err
+					}
+				}
+
+				return nil // dealt with children, get out
+
+			case part.ArrayIndex.Index != nil:
+				idx := *part.ArrayIndex.Index
+				if idx >= len(typedObj) || idx >= len(typedExistingObj) {
+					return nil
+				}
+
+				obj = typedObj[idx]
+				existingObj = typedExistingObj[idx]
+
+			default:
+				panic(fmt.Sprintf("Unknown array index: %#v", part.ArrayIndex))
+			}
+
+		default:
+			panic(fmt.Sprintf("Unexpected path part: %#v", part))
+		}
+	}
+
+	panic("unreachable")
+}
+
+func (t ListMergeKeyMod) alignList(obj, existingObj map[string]interface{}, key string) {
+	list, ok := obj[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	existingList, ok := existingObj[key].([]interface{})
+	if !ok {
+		return
+	}
+
+	existingPositions, unique := t.keyPositions(existingList)
+	if !unique {
+		return // ambiguous, hence leave as is
+	}
+
+	if _, unique := t.keyPositions(list); !unique {
+		return
+	}
+
+	// Items that are not found in existing list are placed at the end
+	ranks := make([]int, len(list))
+	for i, item := range list {
+		ranks[i] = len(existingList) + i
+		if val, found := t.keyValue(item); found {
+			if pos, found := existingPositions[val]; found {
+				ranks[i] = pos
+			}
+		}
+	}
+
+	idxs := make([]int, len(list))
+	for i := range idxs {
+		idxs[i] = i
+	}
+
+	sort.SliceStable(idxs, func(i, j int) bool { return ranks[idxs[i]] < ranks[idxs[j]] })
+
+	alignedList := make([]interface{}, len(list))
+	for i, idx := range idxs {
+		alignedList[i] = list[idx]
+	}
+
+	obj[key] = alignedList
+}
+
+func (t ListMergeKeyMod) keyPositions(list []interface{}) (map[string]int, bool) {
+	result := map[string]int{}
+	for i, item := range list {
+		if val, found := t.keyValue(item); found {
+			if _, dup := result[val]; dup {
+				return nil, false
+			}
+			result[val] = i
+		}
+	}
+	return result, true
+}
+
+func (t ListMergeKeyMod) keyValue(item interface{}) (string, bool) {
+	typedItem, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	val, found := typedItem[t.MergeKey]
+	if !found {
+		return "", false
+	}
+	return fmt.Sprintf("%v", val), true
+}
+
+// correspondingItem finds existing list item for nested paths;
+// items are matched by name if present (e.g. containers) since
+// their positions may differ between new and existing lists
+func (t ListMergeKeyMod) correspondingItem(existingList []interface{}, item interface{}, idx int) interface{} {
+	if typedItem, ok := item.(map[string]interface{}); ok {
+		if name, found := typedItem["name"].(string); found {
+			for _, existingItem := range existingList {
+				if typedExistingItem, ok := existingItem.(map[string]interface{}); ok {
+					if typedExistingItem["name"] == name {
+						return existingItem
+					}
+				}
+			}
+			return nil
+		}
+	}
+
+	if idx < len(existingList) {
+		return existingList[idx]
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package resources_test
+
+import (
+	"testing"
+
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestModListMergeKey(t *testing.T) {
+	containersPath := ctlres.NewPathFromStrings([]string{"spec", "containers"})
+
+	portsPath := ctlres.NewPathFromStrings([]string{"spec", "containers"})
+	portsPath = append(portsPath, ctlres.NewPathPartFromIndexAll(), ctlres.NewPathPartFromString("ports"))
+
+	exs := []modListMergeKeyExample{
+		{
+			Description: "reorders items to follow existing order, placing new items at the end",
+			Res: `
+spec:
+  containers:
+  - name: c
+  - name: b
+  - name: a`,
+			ExistingRes: `
+spec:
+  containers:
+  - name: a
+  - name: b`,
+			Path:     containersPath,
+			MergeKey: "name",
+			Expected: `
+spec:
+  containers:
+  - name: a
+  - name: b
+  - name: c`,
+		},
+		{
+			Description: "leaves list as is when merge keys are not unique",
+			Res: `
+spec:
+  containers:
+  - name: b
+  - name: a
+  - name: a`,
+			ExistingRes: `
+spec:
+  containers:
+  - name: a
+  - name: b`,
+			Path:     containersPath,
+			MergeKey: "name",
+			Expected: `
+spec:
+  containers:
+  - name: b
+  - name: a
+  - name: a`,
+		},
+		{
+			Description: "matches nested lists by item name",
+			Res: `
+spec:
+  containers:
+  - name: b
+    ports:
+    - containerPort: 80
+    - containerPort: 443
+  - name: a
+    ports:
+    - containerPort: 90
+    - containerPort: 91`,
+			ExistingRes: `
+spec:
+  containers:
+  - name: a
+    ports:
+    - containerPort: 91
+    - containerPort: 90
+  - name: b
+    ports:
+    - containerPort: 443
+    - containerPort: 80`,
+			Path:     portsPath,
+			MergeKey: "containerPort",
+			Expected: `
+spec:
+  containers:
+  - name: b
+    ports:
+    - containerPort: 443
+    - containerPort: 80
+  - name: a
+    ports:
+    - containerPort: 91
+    - containerPort: 90`,
+		},
+		{
+			Description: "leaves resource unmodified when existing does not have list",
+			Res: `
+spec:
+  containers:
+  - name: b
+  - name: a`,
+			ExistingRes: `
+spec: {}`,
+			Path:     containersPath,
+			MergeKey: "name",
+			Expected: `
+spec:
+  containers:
+  - name: b
+  - name: a`,
+		},
+	}
+
+	for _, ex := range exs {
+		ex.Check(t)
+	}
+}
+
+type modListMergeKeyExample struct {
+	Description string
+	Res         string
+	ExistingRes string
+	Path        ctlres.Path
+	MergeKey    string
+	Expected    string
+}
+
+func (e modListMergeKeyExample) Check(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(e.Res))
+
+	ress := map[ctlres.FieldCopyModSource]ctlres.Resource{
+		ctlres.FieldCopyModSourceNew:      res.DeepCopy(),
+		ctlres.FieldCopyModSourceExisting: ctlres.MustNewResourceFromBytes([]byte(e.ExistingRes)),
+	}
+
+	err := ctlres.ListMergeKeyMod{
+		ResourceMatcher: ctlres.AllResourceMatcher{},
+		Path:            e.Path,
+		MergeKey:        e.MergeKey,
+	}.ApplyFromMultiple(res, ress)
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	resultBs, err := res.AsYAMLBytes()
+	if err != nil {
+		t.Fatalf("Expected no err, but was %s", err)
+	}
+
+	expectEqualsStripped(t, e.Description, string(resultBs), e.Expected)
+}
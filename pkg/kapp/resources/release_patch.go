@@ -0,0 +1,120 @@
+package resources
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const (
+	kappOriginalAnnKeyPrefix = "kapp.k14s.io/original"
+)
+
+// ReleasePatch removes kapp ownership from a resource: app and association
+// labels (including ones set on pod templates via ownership label rules),
+// identity annotation and original (last applied) annotations
+type ReleasePatch struct {
+	resource    Resource
+	appLabelKey string
+	olmFunc     OwnershipLabelModsFunc
+	lsmFunc     LabelScopingModsFunc
+}
+
+func NewReleasePatch(resource Resource, appLabelKey string,
+	olmFunc OwnershipLabelModsFunc, lsmFunc LabelScopingModsFunc) ReleasePatch {
+
+	return ReleasePatch{resource, appLabelKey, olmFunc, lsmFunc}
+}
+
+func (p ReleasePatch) AsBytes() ([]byte, error) {
+	anns := map[string]interface{}{
+		// Identity annotation is always included since it's stripped
+		// from resources returned by IdentifiedResources
+		kappIdentityAnnKey: nil, // null removes key in merge patch
+	}
+
+	for key := range p.resource.Annotations() {
+		if strings.HasPrefix(key, kappOriginalAnnKeyPrefix) {
+			anns[key] = nil
+		}
+	}
+
+	assocLabelKey := NewAssociationLabel(p.resource).Key()
+
+	mergePatch := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				p.appLabelKey: nil,
+				assocLabelKey: nil,
+			},
+			"annotations": anns,
+		},
+	}
+
+	// Only paths of mods are used, hence label values do not matter
+	ownershipLabels := map[string]string{p.appLabelKey: "", assocLabelKey: ""}
+	labelScoped := p.labelScoped()
+
+	for _, mod := range p.olmFunc(ownershipLabels) {
+		// Merge patch cannot address list items (e.g. volume claim templates)
+		if !mod.ResourceMatcher.Matches(p.resource) || mod.Path.ContainsNonMapKeys() {
+			continue
+		}
+
+		labels := map[string]interface{}{assocLabelKey: nil}
+
+		// Selectors are not changed (they are immutable for most controllers),
+		// hence app label has to stay in pod templates to keep matching them
+		if !labelScoped {
+			labels[p.appLabelKey] = nil
+		}
+
+		p.mergeAtPath(mergePatch, mod.Path.AsStrings(), labels)
+	}
+
+	return json.Marshal(mergePatch)
+}
+
+// labelScoped indicates whether resource's selectors include app label
+func (p ReleasePatch) labelScoped() bool {
+	for _, mod := range p.lsmFunc(map[string]string{p.appLabelKey: ""}) {
+		if !mod.ResourceMatcher.Matches(p.resource) || mod.Path.ContainsNonMapKeys() {
+			continue
+		}
+
+		var obj interface{} = p.resource.DeepCopyRaw()
+
+		for _, key := range mod.Path.AsStrings() {
+			typedObj, ok := obj.(map[string]interface{})
+			if !ok {
+				obj = nil
+				break
+			}
+			obj = typedObj[key]
+		}
+
+		if selector, ok := obj.(map[string]interface{}); ok {
+			if _, found := selector[p.appLabelKey]; found {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (ReleasePatch) mergeAtPath(patch map[string]interface{}, path []string, kvs map[string]interface{}) {
+	for _, key := range path {
+		nextPatch, ok := patch[key].(map[string]interface{})
+		if !ok {
+			nextPatch = map[string]interface{}{}
+			patch[key] = nextPatch
+		}
+		patch = nextPatch
+	}
+
+	for k, v := range kvs {
+		if _, found := patch[k]; !found {
+			patch[k] = v
+		}
+	}
+}
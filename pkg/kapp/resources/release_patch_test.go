@@ -0,0 +1,95 @@
+package resources_test
+
+import (
+	"testing"
+
+	ctlconf "github.com/k14s/kapp/pkg/kapp/config"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestReleasePatch(t *testing.T) {
+	res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  labels:
+    kapp.k14s.io/app: "123"
+    kapp.k14s.io/association: v1.abc
+    other: label
+  annotations:
+    kapp.k14s.io/original: "{}"
+    kapp.k14s.io/original-diff-md5: abc
+    other: ann
+`))
+
+	patchBs, err := newReleasePatch(t, res).AsBytes()
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	expectedPatch := `{"metadata":{"annotations":{"kapp.k14s.io/identity":null,` +
+		`"kapp.k14s.io/original":null,"kapp.k14s.io/original-diff-md5":null},` +
+		`"labels":{"kapp.k14s.io/app":null,"kapp.k14s.io/association":null}}}`
+
+	expectEquals(t, "release patch", string(patchBs), expectedPatch)
+}
+
+func TestReleasePatchPodTemplate(t *testing.T) {
+	exs := []struct {
+		Description   string
+		Selector      string
+		ExpectedPatch string
+	}{
+		{
+			"scoped selector keeps app label in pod template",
+			`{matchLabels: {app: web, kapp.k14s.io/app: "123"}}`,
+			`{"metadata":{"annotations":{"kapp.k14s.io/identity":null},` +
+				`"labels":{"kapp.k14s.io/app":null,"kapp.k14s.io/association":null}},` +
+				`"spec":{"template":{"metadata":{"labels":{"kapp.k14s.io/association":null}}}}}`,
+		},
+		{
+			"unscoped selector",
+			`{matchLabels: {app: web}}`,
+			`{"metadata":{"annotations":{"kapp.k14s.io/identity":null},` +
+				`"labels":{"kapp.k14s.io/app":null,"kapp.k14s.io/association":null}},` +
+				`"spec":{"template":{"metadata":{"labels":{"kapp.k14s.io/app":null,"kapp.k14s.io/association":null}}}}}`,
+		},
+	}
+
+	for _, ex := range exs {
+		res := ctlres.MustNewResourceFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dep
+  labels:
+    kapp.k14s.io/app: "123"
+    kapp.k14s.io/association: v1.abc
+spec:
+  selector: ` + ex.Selector + `
+  template:
+    metadata:
+      labels:
+        app: web
+        kapp.k14s.io/app: "123"
+        kapp.k14s.io/association: v1.abc
+`))
+
+		patchBs, err := newReleasePatch(t, res).AsBytes()
+		if err != nil {
+			t.Fatalf("Expected no error: %s", err)
+		}
+
+		expectEquals(t, ex.Description, string(patchBs), ex.ExpectedPatch)
+	}
+}
+
+func newReleasePatch(t *testing.T, res ctlres.Resource) ctlres.ReleasePatch {
+	_, conf, err := ctlconf.NewConfFromResourcesWithDefaults(nil)
+	if err != nil {
+		t.Fatalf("Expected no error: %s", err)
+	}
+
+	return ctlres.NewReleasePatch(res, "kapp.k14s.io/app", conf.OwnershipLabelMods(), conf.LabelScopingMods())
+}
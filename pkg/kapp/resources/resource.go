@@ -31,11 +31,13 @@ type Resource interface {
 	Annotations() map[string]string
 	Labels() map[string]string
 	OwnerRefs() []metav1.OwnerReference
+	Finalizers() []string
 	Status() map[string]interface{}
 
 	CreatedAt() time.Time
 	IsProvisioned() bool
 	IsDeleting() bool
+	DeletingSince() time.Time
 	UID() string
 
 	Equal(res Resource) bool
@@ -192,12 +194,20 @@ func (r *ResourceImpl) IsProvisioned() bool {
 
 func (r *ResourceImpl) IsDeleting() bool { return r.un.GetDeletionTimestamp() != nil }
 
+func (r *ResourceImpl) DeletingSince() time.Time {
+	if ts := r.un.GetDeletionTimestamp(); ts != nil {
+		return ts.Time
+	}
+	return time.Time{}
+}
+
 func (r *ResourceImpl) MarkTransient(transient bool) { r.transient = transient }
 func (r *ResourceImpl) Transient() bool              { return r.transient }
 
 func (r *ResourceImpl) Annotations() map[string]string     { return r.un.GetAnnotations() }
 func (r *ResourceImpl) Labels() map[string]string          { return r.un.GetLabels() }
 func (r *ResourceImpl) OwnerRefs() []metav1.OwnerReference { return r.un.GetOwnerReferences() }
+func (r *ResourceImpl) Finalizers() []string               { return r.un.GetFinalizers() }
 
 func (r *ResourceImpl) Status() map[string]interface{} {
 	if r.un.Object != nil {
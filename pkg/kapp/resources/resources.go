@@ -285,7 +285,12 @@ func (c *Resources) doneRetryingErr(err error) bool {
 	return !retry
 }
 
-func (c *Resources) Delete(resource Resource) error {
+type DeleteOpts struct {
+	// Defaults to background propagation
+	PropagationPolicy metav1.DeletionPropagation
+}
+
+func (c *Resources) Delete(resource Resource, opts DeleteOpts) error {
 	if resourcesDebug {
 		t1 := time.Now().UTC()
 		defer func() { fmt.Printf("delete %s\n", time.Now().UTC().Sub(t1)) }()
@@ -302,9 +307,11 @@ func (c *Resources) Delete(resource Resource) error {
 	}
 
 	if resType.Deletable() {
-		// TODO is setting deletion policy a correct thing to do?
 		// https://kubernetes.io/docs/concepts/workloads/controllers/garbage-collection/#setting-the-cascading-deletion-policy
-		delPol := metav1.DeletePropagationBackground
+		delPol := opts.PropagationPolicy
+		if len(delPol) == 0 {
+			delPol = metav1.DeletePropagationBackground
+		}
 		delOpts := &metav1.DeleteOptions{PropagationPolicy: &delPol}
 
 		// Some resources may not have UID (example: PodMetrics.metrics.k8s.io)
@@ -0,0 +1,101 @@
+package resources
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	dryRunAllValue = "All"
+)
+
+// DryRunCreate, DryRunUpdate and DryRunDelete submit requests with server-side
+// dry run enabled: requests go through admission (including webhooks) and
+// validation, but changes are not persisted. Vendored dynamic client does not
+// support request options, hence requests are made directly.
+
+func (c *Resources) DryRunCreate(resource Resource) error {
+	bs, err := resource.AsCompactBytes()
+	if err != nil {
+		return err
+	}
+
+	req := c.restClient().Post().SetHeader("Content-Type", "application/json").Body(bs)
+
+	return c.dryRun(req, "Dry run creating", resource, false)
+}
+
+func (c *Resources) DryRunUpdate(resource Resource) error {
+	bs, err := resource.AsCompactBytes()
+	if err != nil {
+		return err
+	}
+
+	req := c.restClient().Put().SetHeader("Content-Type", "application/json").Body(bs)
+
+	return c.dryRun(req, "Dry run updating", resource, true)
+}
+
+func (c *Resources) DryRunPatch(resource Resource, patchType types.PatchType, data []byte) error {
+	return c.dryRun(c.restClient().Patch(patchType).Body(data), "Dry run patching", resource, true)
+}
+
+func (c *Resources) DryRunDelete(resource Resource, opts DeleteOpts) error {
+	delPol := opts.PropagationPolicy
+	if len(delPol) == 0 {
+		delPol = metav1.DeletePropagationBackground
+	}
+
+	bs, err := json.Marshal(metav1.DeleteOptions{PropagationPolicy: &delPol})
+	if err != nil {
+		return err
+	}
+
+	req := c.restClient().Delete().SetHeader("Content-Type", "application/json").Body(bs)
+
+	return c.dryRun(req, "Dry run deleting", resource, true)
+}
+
+func (c *Resources) restClient() rest.Interface {
+	return c.coreClient.Discovery().RESTClient()
+}
+
+func (c *Resources) dryRun(req *rest.Request, action string, resource Resource, withName bool) error {
+	req, _, err := c.rawRequest(req, resource, withName)
+	if err != nil {
+		return err
+	}
+
+	err = req.Param("dryRun", dryRunAllValue).Do().Error()
+	if err != nil {
+		return c.resourceErr(err, action, resource)
+	}
+
+	return nil
+}
+
+func (c *Resources) rawRequest(req *rest.Request, resource Resource, withName bool) (*rest.Request, ResourceType, error) {
+	resType, err := c.resourceTypes.Find(resource)
+	if err != nil {
+		return nil, ResourceType{}, err
+	}
+
+	gvr := resType.GroupVersionResource
+
+	path := []string{"/apis", gvr.Group, gvr.Version}
+	if len(gvr.Group) == 0 {
+		path = []string{"/api", gvr.Version}
+	}
+	if resType.Namespaced() {
+		path = append(path, "namespaces", resource.Namespace())
+	}
+	path = append(path, gvr.Resource)
+	if withName {
+		path = append(path, resource.Name())
+	}
+
+	return req.AbsPath(path...), resType, nil
+}
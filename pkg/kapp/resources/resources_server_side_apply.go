@@ -0,0 +1,84 @@
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/k14s/kapp/pkg/kapp/util"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// Not available in vendored apimachinery
+	applyPatchType types.PatchType = "application/apply-patch+yaml"
+)
+
+// ServerSideApply submits resource as an apply patch so that API server merges
+// it with fields owned by other field managers. Conflicting fields are
+// reported as Conflict errors (conflicts are not forced).
+func (c *Resources) ServerSideApply(resource Resource, fieldManager string) (Resource, error) {
+	if resourcesDebug {
+		t1 := time.Now().UTC()
+		defer func() { fmt.Printf("server-side apply %s\n", time.Now().UTC().Sub(t1)) }()
+	}
+
+	var appliedBs []byte
+	var resType ResourceType
+
+	err := util.Retry(time.Second, time.Minute, func() (bool, error) {
+		// Request body can only be read once, hence new request per attempt
+		var req *rest.Request
+		var err error
+
+		req, resType, err = c.serverSideApplyRequest(resource, fieldManager)
+		if err != nil {
+			return true, err
+		}
+
+		appliedBs, err = req.Do().Raw()
+		if err != nil {
+			return c.doneRetryingErr(err), c.resourceErr(err, "Applying (server-side)", resource)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var appliedUn unstructured.Unstructured
+
+	err = json.Unmarshal(appliedBs, &appliedUn.Object)
+	if err != nil {
+		return nil, fmt.Errorf("Unmarshaling server-side applied resource: %s", err)
+	}
+
+	return NewResourceUnstructured(appliedUn, resType), nil
+}
+
+func (c *Resources) DryRunServerSideApply(resource Resource, fieldManager string) error {
+	req, _, err := c.serverSideApplyRequest(resource, fieldManager)
+	if err != nil {
+		return err
+	}
+
+	err = req.Param("dryRun", dryRunAllValue).Do().Error()
+	if err != nil {
+		return c.resourceErr(err, "Dry run applying (server-side)", resource)
+	}
+
+	return nil
+}
+
+func (c *Resources) serverSideApplyRequest(resource Resource, fieldManager string) (*rest.Request, ResourceType, error) {
+	bs, err := resource.AsCompactBytes()
+	if err != nil {
+		return nil, ResourceType{}, err
+	}
+
+	req := c.restClient().Patch(applyPatchType).Param("fieldManager", fieldManager).Body(bs)
+
+	return c.rawRequest(req, resource, true)
+}
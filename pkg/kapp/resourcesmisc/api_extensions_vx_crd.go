@@ -25,6 +25,17 @@ func (s ApiExtensionsVxCRD) IsDoneApplying() DoneApplyState {
 	return DoneApplyState{Done: allTrue, Successful: allTrue, Message: msg}
 }
 
+// DefinesResource returns true if resource is of a kind defined by CRD
+// (it also returns true when CRD contents cannot be determined)
+func (s ApiExtensionsVxCRD) DefinesResource(res ctlres.Resource) bool {
+	contents, err := s.contents()
+	if err != nil {
+		return true
+	}
+
+	return contents.Spec.Group == res.APIGroup() && contents.Spec.Names.Kind == res.Kind()
+}
+
 func (s ApiExtensionsVxCRD) contents() (crdObj, error) {
 	bs, err := s.resource.AsYAMLBytes()
 	if err != nil {
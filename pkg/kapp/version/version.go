@@ -0,0 +1,5 @@
+package version
+
+const (
+	Version = "0.16.0"
+)
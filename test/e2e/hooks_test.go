@@ -0,0 +1,99 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key: val
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pre-deploy-hook
+  annotations:
+    kapp.k14s.io/hook: pre-deploy
+    kapp.k14s.io/hook-delete-policy: hook-succeeded
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: post-deploy-hook
+  annotations:
+    kapp.k14s.io/hook: post-deploy
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: pre-delete-hook
+  annotations:
+    kapp.k14s.io/hook: pre-delete
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: post-delete-hook
+  annotations:
+    kapp.k14s.io/hook: post-delete
+`
+
+	name := "test-hooks"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app with hooks", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		NewPresentClusterResource("configmap", "cm", env.Namespace, kubectl)
+		NewPresentClusterResource("configmap", "post-deploy-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "pre-deploy-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "pre-delete-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "post-delete-hook", env.Namespace, kubectl)
+	})
+
+	logger.Section("deploy same app again recreates hooks", func() {
+		prev := NewPresentClusterResource("configmap", "post-deploy-hook", env.Namespace, kubectl)
+
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+
+		curr := NewPresentClusterResource("configmap", "post-deploy-hook", env.Namespace, kubectl)
+		if prev.UID() == curr.UID() {
+			t.Fatalf("Expected post-deploy hook to be recreated")
+		}
+	})
+
+	logger.Section("delete app runs delete hooks and deletes them (with default delete policy)", func() {
+		out, _ := kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{})
+
+		if !strings.Contains(out, "running 1 pre-delete hooks") || !strings.Contains(out, "running 1 post-delete hooks") {
+			t.Fatalf("Expected delete hooks to run, but output was: %s", out)
+		}
+
+		NewMissingClusterResource(t, "configmap", "cm", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "post-deploy-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "pre-delete-hook", env.Namespace, kubectl)
+		NewMissingClusterResource(t, "configmap", "post-delete-hook", env.Namespace, kubectl)
+
+		_, err := kapp.RunWithOpts([]string{"inspect", "-a", name}, RunOpts{AllowError: true})
+		if err == nil {
+			t.Fatalf("Expected app to be fully deleted")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+)
+
+func TestRename(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  key: value
+`
+
+	name := "test-rename"
+	newName := "test-rename-new"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+		kapp.RunWithOpts([]string{"delete", "-a", newName}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy app", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	logger.Section("rename app", func() {
+		kapp.RunWithOpts([]string{"rename", "-a", name, "--new-name", newName}, RunOpts{})
+	})
+
+	logger.Section("check app changes were carried over", func() {
+		out, _ := kapp.RunWithOpts([]string{"app-change", "list", "-a", newName, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		if len(resp.Tables[0].Rows) != 1 {
+			t.Fatalf("Expected to see one app change, but did not: '%s'", out)
+		}
+		if !strings.HasPrefix(resp.Tables[0].Rows[0]["name"], newName+"-change-") {
+			t.Fatalf("Expected app change to be renamed, but was not: '%s'", out)
+		}
+
+		_, err := kapp.RunWithOpts([]string{"inspect", "-a", name}, RunOpts{AllowError: true})
+		if err == nil {
+			t.Fatalf("Expected previous app to be gone")
+		}
+	})
+
+	logger.Section("check resources are still owned by app", func() {
+		out, _ := kapp.RunWithOpts([]string{"inspect", "-a", newName, "--json"}, RunOpts{})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		if len(resp.Tables[0].Rows) != 1 || resp.Tables[0].Rows[0]["name"] != "test-cm" {
+			t.Fatalf("Expected to see app resources, but did not: '%s'", out)
+		}
+	})
+}
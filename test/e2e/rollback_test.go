@@ -0,0 +1,60 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRollbackAcrossMaskedSecret(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: secret
+stringData:
+  password: pass1
+`
+
+	yaml2 := strings.Replace(yaml1, "pass1", "pass2", -1)
+
+	name := "test-rollback-masked-secret"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	secretPassword := func() string {
+		return kubectl.Run([]string{"get", "secret", "secret", "-o", "jsonpath={.data.password}"})
+	}
+
+	logger.Section("deploy app with secret (app state in config maps)", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml2)})
+
+		if secretPassword() != "cGFzczI=" {
+			t.Fatalf("Expected secret to have updated password")
+		}
+	})
+
+	logger.Section("rollback refuses to use app changes with masked values", func() {
+		_, err := kapp.RunWithOpts([]string{"rollback", "-a", name, "--steps", "1"}, RunOpts{AllowError: true})
+		if err == nil {
+			t.Fatalf("Expected rollback to fail")
+		}
+		if !strings.Contains(err.Error(), "successful non-partial app changes") {
+			t.Fatalf("Expected rollback to fail due to partial app changes, but was: %s", err)
+		}
+
+		if secretPassword() != "cGFzczI=" {
+			t.Fatalf("Expected secret to be left as is")
+		}
+	})
+}
@@ -0,0 +1,143 @@
+package e2e
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	uitest "github.com/cppforlife/go-cli-ui/ui/test"
+	ctlres "github.com/k14s/kapp/pkg/kapp/resources"
+)
+
+func TestUpdateMergePatchAndServerSideApply(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yaml1 := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+data:
+  key1: val1
+  key2: val2
+`
+
+	yamlWithStrategy := func(strategy string) string {
+		return `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  annotations:
+    kapp.k14s.io/update-strategy: ` + strategy + `
+data:
+  key1: val1-` + strategy + `
+`
+	}
+
+	name := "test-update-strategy"
+	objKind := "configmap"
+	objName := "cm"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	logger.Section("deploy basic config map", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yaml1)})
+	})
+
+	for _, strategy := range []string{"merge-patch", "server-side-apply"} {
+		logger.Section("deploy update with "+strategy+" strategy", func() {
+			prev := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+
+			kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yamlWithStrategy(strategy))})
+			curr := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+
+			if prev.UID() != curr.UID() {
+				t.Fatalf("Expected object to be updated, but found different UID")
+			}
+
+			expectedData := map[string]interface{}{"key1": "val1-" + strategy}
+			if !reflect.DeepEqual(curr.RawPath(ctlres.NewPathFromStrings([]string{"data"})), expectedData) {
+				t.Fatalf("Expected data to be updated, but was: %#v", curr.RawPath(ctlres.NewPathFromStrings([]string{"data"})))
+			}
+		})
+
+		logger.Section("deploy same configuration with "+strategy+" strategy", func() {
+			out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json"},
+				RunOpts{IntoNs: true, StdinReader: strings.NewReader(yamlWithStrategy(strategy))})
+
+			resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+			if resp.Tables[0].Notes[0] != "Op:      0 create, 0 delete, 0 update, 0 noop" {
+				t.Fatalf("Expected to see no changes, but did not: '%s'", out)
+			}
+		})
+	}
+}
+
+func TestCreateSkipIfExists(t *testing.T) {
+	env := BuildEnv(t)
+	logger := Logger{}
+	kapp := Kapp{t, env.Namespace, env.KappBinaryPath, logger}
+	kubectl := Kubectl{t, env.Namespace, logger}
+
+	yamlWithData := func(val string) string {
+		return `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: bootstrap
+  annotations:
+    kapp.k14s.io/create-strategy: skip-if-exists
+stringData:
+  key1: ` + val + `
+`
+	}
+
+	name := "test-create-skip-if-exists"
+	objKind := "secret"
+	objName := "bootstrap"
+	cleanUp := func() {
+		kapp.RunWithOpts([]string{"delete", "-a", name}, RunOpts{AllowError: true})
+	}
+
+	cleanUp()
+	defer cleanUp()
+
+	dataPath := ctlres.NewPathFromStrings([]string{"data", "key1"})
+
+	logger.Section("deploy secret", func() {
+		kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name}, RunOpts{IntoNs: true, StdinReader: strings.NewReader(yamlWithData("val1"))})
+
+		curr := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+		if curr.RawPath(dataPath) != "dmFsMQ==" {
+			t.Fatalf("Expected secret to be created, but data was: %#v", curr.RawPath(dataPath))
+		}
+	})
+
+	logger.Section("deploy changed secret", func() {
+		out, _ := kapp.RunWithOpts([]string{"deploy", "-f", "-", "-a", name, "--json"},
+			RunOpts{IntoNs: true, StdinReader: strings.NewReader(yamlWithData("val2"))})
+
+		resp := uitest.JSONUIFromBytes(t, []byte(out))
+
+		if resp.Tables[0].Notes[0] != "Op:      0 create, 0 delete, 0 update, 0 noop" {
+			t.Fatalf("Expected to see no changes, but did not: '%s'", out)
+		}
+
+		curr := NewPresentClusterResource(objKind, objName, env.Namespace, kubectl)
+		if curr.RawPath(dataPath) != "dmFsMQ==" {
+			t.Fatalf("Expected secret to be left as is, but data was: %#v", curr.RawPath(dataPath))
+		}
+	})
+}